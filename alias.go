@@ -4,16 +4,43 @@
 
 // The alias package picks items from a discrete distribution
 // efficiently using the alias method.
+//
+// Drawing never allocates: Gen, GenHash, GenKey with the default hash, GenN,
+// GenFromWords, MultiGen, GenInRange, GenBelow and Quantile make no heap
+// allocations, apart from computing a table's cached probabilities the first
+// time a method needs them. This is checked by the package's tests and will
+// hold in later versions.
+//
+// The package keeps no global random state; every draw uses the rng or
+// random words it's given. This suits TinyGo and WebAssembly targets, where
+// for the smallest footprint tables can be generated ahead of time with
+// WriteGo and loaded with FromStatic, which doesn't copy or decode the
+// table, and drawn from with GenHash or GenFromWords, which don't need
+// math/rand at all.
+// Under TinyGo, profile labels are not set and BuildFile is unavailable.
 package alias
 
 import (
-	"encoding/binary"
+	"encoding/base64"
 	"errors"
 	"math/rand"
+	"sync"
 )
 
 type Alias struct {
 	table []ipiece
+
+	profileLabel string // from Options; see profile.go
+
+	// derived from table on demand; see prob.go
+	pmfOnce  sync.Once
+	pmfCache []float64
+	pmfLive  int // number of indices with nonzero probability
+	cdfOnce  sync.Once
+	cdfCache []float64
+
+	guideOnce  sync.Once
+	guideCache []uint32
 }
 
 type fpiece struct {
@@ -32,30 +59,83 @@ type ipiece struct {
 // creates an alias that returns 0 40% of the time, 1 50% of the time, and
 // 2 10% of the time.
 func New(prob []float64) (*Alias, error) {
+	al, _, err := build(prob, false)
+	return al, err
+}
 
-	// This implementation is based on
-	// http://www.keithschwarz.com/darts-dice-coins/
+// build does the work of New. It also returns the normalized probability
+// mass that floating point error left short in slots that should have been
+// full.
+//
+// If allowZero is set, zero probabilities are accepted as long as the total
+// is positive. Their slots get a threshold of zero, and always take their
+// alias.
+func build(prob []float64, allowZero bool) (*Alias, float64, error) {
+	return buildWith(prob, allowZero, vose)
+}
+
+// buildWith is build, pairing off the items with pair.
+func buildWith(prob []float64, allowZero bool, pair pairFunc) (*Alias, float64, error) {
+	total, err := checkProb(prob, allowZero)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var al Alias
+	al.table = make([]ipiece, len(prob))
+	leftover := fill(al.table, prob, total, pair)
 
+	return &al, leftover, nil
+}
+
+// checkProb checks that prob is acceptable to build, and returns its total.
+func checkProb(prob []float64, allowZero bool) (float64, error) {
 	n := len(prob)
 
 	if n < 1 {
-		return nil, errors.New("too few probabilities")
+		return 0, errors.New("too few probabilities")
 	}
 
 	if int(uint32(n)) != n {
-		return nil, errors.New("too many probabilities")
+		return 0, errors.New("too many probabilities")
 	}
 
 	total := float64(0)
 	for _, v := range prob {
-		if v <= 0 {
-			return nil, errors.New("a probability is non-positive")
+		if v < 0 || (v == 0 && !allowZero) {
+			return 0, errors.New("a probability is non-positive")
 		}
 		total += v
 	}
+	if total <= 0 {
+		return 0, errors.New("probabilities sum to zero")
+	}
 
-	var al Alias
-	al.table = make([]ipiece, n)
+	return total, nil
+}
+
+// fill builds the table for prob, which sums to total, into table, pairing
+// off the items with pair. Every slot of table is written. It returns the
+// leftover mass, as for build.
+func fill(table []ipiece, prob []float64, total float64, pair pairFunc) float64 {
+	return pair(prob, total, func(i uint32, p float64, alias uint32) {
+		table[i] = ipiece{uint32(p * (1<<31 - 1)), alias}
+	})
+}
+
+// pairFunc is vose or sweep.
+type pairFunc func(prob []float64, total float64, set func(i uint32, p float64, alias uint32)) float64
+
+// vose pairs off the items of prob, which sums to total, calling set once
+// for every slot with the probability in [0,1] that the slot keeps for its
+// own index and the alias it gives the rest to. It returns the leftover
+// mass, as for build.
+func vose(prob []float64, total float64, set func(i uint32, p float64, alias uint32)) float64 {
+
+	// This implementation is based on
+	// http://www.keithschwarz.com/darts-dice-coins/
+
+	n := len(prob)
 
 	// Michael Vose's algorithm
 
@@ -91,8 +171,7 @@ func New(prob []float64) (*Alias, error) {
 		g := twins[lgBot]
 		lgBot++
 
-		al.table[l.alias].prob = uint32(l.prob * (1<<31 - 1))
-		al.table[l.alias].alias = g.alias
+		set(l.alias, l.prob, g.alias)
 
 		g.prob = (g.prob + l.prob) - 1
 
@@ -108,65 +187,87 @@ func New(prob []float64) (*Alias, error) {
 
 	// clear out any remaining blocks
 	for i := n - 1; i >= lgBot; i-- {
-		al.table[twins[i].alias].prob = 1<<31 - 1
+		set(twins[i].alias, 1, 0)
 	}
 
 	// there shouldn't be anything here, but sometimes floating point
 	// errors send a probability just under 1.
+	leftover := float64(0)
 	for i := 0; i <= smTop; i++ {
-		al.table[twins[i].alias].prob = 1<<31 - 1
+		leftover += 1 - twins[i].prob
+		set(twins[i].alias, 1, 0)
 	}
 
-	return &al, nil
+	return leftover / float64(n)
+}
+
+// Len returns the number of items.
+func (al *Alias) Len() int {
+	return len(al.table)
 }
 
 // Generates a random number according to the distribution using the rng passed.
+//
+// Gen never retries: every call takes exactly one value from rng (a single
+// Int63 from its Source) and does a constant amount of work, regardless of
+// the table's size or the shape of the distribution. The worst case is the
+// same as the average case.
 func (al *Alias) Gen(rng *rand.Rand) uint32 {
-	ri := uint32(rng.Int31())
+	return al.genFrom(uint32(rng.Int31()))
+}
+
+// genFrom picks an index given a uniform random value in [0,2^31).
+//
+// A threshold of zero keeps nothing. Every other slot w only sees values of
+// ri >= w, so this only matters for slot 0, which would otherwise return its
+// own index for ri == 0 even if its weight were zero.
+func (al *Alias) genFrom(ri uint32) uint32 {
 	w := ri % uint32(len(al.table))
-	if ri > al.table[w].prob {
+	if ri > al.table[w].prob || al.table[w].prob == 0 {
 		return al.table[w].alias
 	}
 	return w
 }
 
-// MarshalBinary implements encoding.BinaryMarshaller.
+// MarshalBinary implements encoding.BinaryMarshaller. encoding/gob uses it
+// too, so an *Alias can be sent through gob-based RPC and caches as is.
+//
+// The encoding is version 2 of the format, which is
+//
+//	magic    4 bytes, "ALS" and 0xA1
+//	version  little endian uint32, 2
+//	count    little endian uint32, the number of slots
+//	slots    count slots in the zero Layout
+//	checksum little endian uint32, the CRC-32 (IEEE) of everything before it
+//
+// so that corrupt or truncated data is caught when it's unmarshalled.
 func (al *Alias) MarshalBinary() ([]byte, error) {
-	out := make([]byte, len(al.table)*8)
-	for i, piece := range al.table {
-		bin := out[i*8 : 8+i*8]
-		binary.LittleEndian.PutUint32(bin[0:4], piece.prob)
-		binary.LittleEndian.PutUint32(bin[4:8], piece.alias)
-	}
-	return out, nil
+	return al.marshalV2(), nil
 }
 
-// UnmarshalBinary implements encoding.BinaryUnmarshaller.
+// UnmarshalBinary implements encoding.BinaryUnmarshaller. It reads both the
+// v2 format and the headerless v1 format, the zero Layout, which earlier
+// versions wrote.
 func (al *Alias) UnmarshalBinary(p []byte) error {
-	if len(p)%8 != 0 {
-		return errors.New("bad data length")
-	}
-
-	if int(uint32(len(p)/8)) != len(p)/8 {
-		return errors.New("data too large")
-	}
-
-	al.table = make([]ipiece, (len(p))/8)
-	for i := range al.table {
-		bin := p[i*8 : 8+i*8]
-		prob := binary.LittleEndian.Uint32(bin[0:4])
-		alias := binary.LittleEndian.Uint32(bin[4:8])
+	return al.unmarshalAny(p)
+}
 
-		if prob >= 1<<31 {
-			return errors.New("bad data: probability out of range")
-		}
-		if alias >= uint32(len(al.table)) {
-			return errors.New("bad data: alias target out of range")
-		}
+// MarshalText implements encoding.TextMarshaler, for storing tables in
+// places that only accept text. The text is the MarshalBinary encoding in
+// standard base64, so it unmarshals to exactly the same table.
+func (al *Alias) MarshalText() ([]byte, error) {
+	data := al.marshalV2()
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(out, data)
+	return out, nil
+}
 
-		al.table[i].prob = prob
-		al.table[i].alias = alias
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (al *Alias) UnmarshalText(p []byte) error {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(p)))
+	n, err := base64.StdEncoding.Decode(data, p)
+	if err != nil {
+		return errors.New("bad data: bad base64")
 	}
-
-	return nil
+	return al.UnmarshalBinary(data[:n])
 }
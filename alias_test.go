@@ -5,6 +5,8 @@
 package alias
 
 import (
+	"bytes"
+	"encoding/gob"
 	"math"
 	"math/rand"
 	"reflect"
@@ -15,22 +17,28 @@ const distributionCount = 1000000
 const errorBound = 0.001
 
 func testDistribution(t *testing.T, dist []float64, seed int64) {
-	sum := float64(0)
-	for i := 0; i < len(dist); i++ {
-		sum += dist[i]
-	}
-
 	a, err := New(dist)
 	if err != nil {
 		t.Error("Got an error during creation:", err)
 		return
 	}
 
+	checkDistribution(t, a.Gen, dist, seed)
+}
+
+// checkDistribution draws from gen and checks the results against the
+// (unnormalized) distribution dist.
+func checkDistribution(t *testing.T, gen func(*rand.Rand) uint32, dist []float64, seed int64) {
+	sum := float64(0)
+	for i := 0; i < len(dist); i++ {
+		sum += dist[i]
+	}
+
 	rng := rand.New(rand.NewSource(seed))
 
 	counts := make([]int64, len(dist))
 	for i := 0; i < distributionCount; i++ {
-		counts[a.Gen(rng)]++
+		counts[gen(rng)]++
 	}
 
 	for i := 0; i < len(dist); i++ {
@@ -49,6 +57,57 @@ func TestDistribution(t *testing.T) {
 	testDistribution(t, []float64{1000, 1, 3, 10}, 61)
 }
 
+func TestMarshalText(t *testing.T) {
+	a, err := New([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 1000})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	text, err := a.MarshalText()
+	if err != nil {
+		t.Fatalf("Couldn't MarshalText: %v", err)
+	}
+
+	a2 := &Alias{}
+	if err := a2.UnmarshalText(text); err != nil {
+		t.Fatalf("Couldn't UnmarshalText: %v", err)
+	}
+	if !reflect.DeepEqual(a, a2) {
+		t.Fatalf("Unmarshalled version was not the same as original")
+	}
+
+	for _, bad := range []string{"not base64!", string(text[:len(text)-8])} {
+		if err := a2.UnmarshalText([]byte(bad)); err == nil {
+			t.Errorf("UnmarshalText(%q) did not fail", bad)
+		}
+	}
+}
+
+func TestGob(t *testing.T) {
+	a, err := New([]float64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	type message struct {
+		Name  string
+		Table *Alias
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(message{"dice", a}); err != nil {
+		t.Fatalf("Couldn't gob encode: %v", err)
+	}
+	var m message
+	if err := gob.NewDecoder(&buf).Decode(&m); err != nil {
+		t.Fatalf("Couldn't gob decode: %v", err)
+	}
+
+	if !reflect.DeepEqual(a, m.Table) {
+		t.Fatalf("Decoded version was not the same as original")
+	}
+}
+
 func TestMarshalBinary(t *testing.T) {
 	distributions := [][]float64{
 		{1},
@@ -0,0 +1,277 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+// The aliasbench package measures the alias package's sampler variants on
+// weights of your choosing, so you can choose between them empirically on
+// your own data and hardware.
+//
+// For example,
+//
+//	results, err := aliasbench.Run(aliasbench.Config{
+//	    N:       100000,
+//	    Weights: func(i int) float64 { return 1 / float64(i+1) },
+//	})
+//
+// measures each variant on a Zipf-like distribution over 100000 items.
+// CompareSamplers does the same for a slice of weights, and also checks the
+// accuracy of each variant's draws.
+package aliasbench
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/encryptio/alias"
+)
+
+// Config describes a benchmark run.
+type Config struct {
+	// N is the number of items.
+	N int
+
+	// Weights returns the weight of item i. It must be positive.
+	Weights func(i int) float64
+
+	// Draws is the number of draws to time for each variant. Values less
+	// than 1 mean 1000000.
+	Draws int
+
+	// Seed seeds the random number generator used for the draws.
+	Seed int64
+}
+
+// Result is the measurement of one variant.
+type Result struct {
+	// Variant names the constructor measured.
+	Variant string
+
+	BuildTime  time.Duration // time taken to build the sampler
+	BuildBytes uint64        // heap memory allocated while building it
+
+	DrawsPerSecond float64
+
+	// WordsPerDraw is the average number of words each draw took from the
+	// random source. A draw from a plain table takes exactly one, so any
+	// more above a variant's minimum is the cost of retries.
+	WordsPerDraw float64
+}
+
+// CompareOptions controls CompareSamplers.
+type CompareOptions struct {
+	// Draws is the number of draws to time and count for each variant.
+	// Values less than 1 mean 1000000.
+	Draws int
+
+	// Seed seeds the random number generator used for the draws.
+	Seed int64
+}
+
+// Comparison is the measurement of one variant by CompareSamplers.
+type Comparison struct {
+	Result
+
+	// TotalVariation is the total variation distance between the frequency
+	// of each item among the draws and its share of the weight: the largest
+	// difference in probability the two give any set of items.
+	TotalVariation float64
+
+	// ChiSquare is Pearson's chi-squared statistic for the draws against
+	// the weights, with one degree of freedom fewer than there are items.
+	// Draws that follow the weights give a value near the degrees of
+	// freedom; much larger values mean a biased sampler.
+	ChiSquare float64
+}
+
+type variant struct {
+	name  string
+	build func(weights []float64) (func(*rand.Rand) uint32, error)
+}
+
+var variants = []variant{
+	{"New", func(weights []float64) (func(*rand.Rand) uint32, error) {
+		a, err := alias.New(weights)
+		if err != nil {
+			return nil, err
+		}
+		return func(rng *rand.Rand) uint32 { return a.Gen(rng) }, nil
+	}},
+	{"NewInt", func(weights []float64) (func(*rand.Rand) uint32, error) {
+		a, err := alias.NewInt(toInts(weights))
+		if err != nil {
+			return nil, err
+		}
+		return func(rng *rand.Rand) uint32 { return a.Gen(rng) }, nil
+	}},
+	{"NewWide", func(weights []float64) (func(*rand.Rand) uint32, error) {
+		wd, err := alias.NewWide(weights)
+		if err != nil {
+			return nil, err
+		}
+		return func(rng *rand.Rand) uint32 { return wd.Gen(rng) }, nil
+	}},
+	{"NewHybrid", func(weights []float64) (func(*rand.Rand) uint32, error) {
+		// a head of up to 16 items, as if they were frequently reweighted
+		split := 16
+		if split > len(weights)-1 {
+			split = len(weights) - 1
+		}
+		h, err := alias.NewHybrid(weights[:split], weights[split:])
+		if err != nil {
+			return nil, err
+		}
+		return func(rng *rand.Rand) uint32 { return h.Gen(rng) }, nil
+	}},
+	{"CDF", func(weights []float64) (func(*rand.Rand) uint32, error) {
+		// the usual alternative: a binary search over running sums
+		cum := make([]float64, len(weights))
+		total := float64(0)
+		for i, w := range weights {
+			total += w
+			cum[i] = total
+		}
+		return func(rng *rand.Rand) uint32 {
+			i := sort.SearchFloat64s(cum, rng.Float64()*total)
+			if i == len(cum) {
+				i--
+			}
+			return uint32(i)
+		}, nil
+	}},
+}
+
+// toInts scales weights to integers for NewInt, with the largest mapped to
+// 2^32 so that no sum can overflow. Weights that would round to zero are
+// raised to one.
+func toInts(weights []float64) []uint64 {
+	max := float64(0)
+	for _, w := range weights {
+		max = math.Max(max, w)
+	}
+
+	out := make([]uint64, len(weights))
+	for i, w := range weights {
+		out[i] = uint64(math.Round(w / max * (1 << 32)))
+		if out[i] == 0 {
+			out[i] = 1
+		}
+	}
+	return out
+}
+
+// Run measures every variant on the weights described by cfg.
+func Run(cfg Config) ([]Result, error) {
+	if cfg.N < 1 {
+		return nil, errors.New("too few items")
+	}
+	if cfg.Weights == nil {
+		return nil, errors.New("no weights")
+	}
+
+	weights := make([]float64, cfg.N)
+	for i := range weights {
+		weights[i] = cfg.Weights(i)
+	}
+
+	comparisons, err := compare(weights, cfg.Draws, cfg.Seed, false)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(comparisons))
+	for i, c := range comparisons {
+		results[i] = c.Result
+	}
+	return results, nil
+}
+
+// CompareSamplers builds every variant for weights, which must be positive,
+// times its construction and draws as Run does, and compares the draws'
+// frequencies with the weights. The variants include a binary search over
+// running sums, the usual alternative to an alias table, as "CDF".
+func CompareSamplers(weights []float64, opts CompareOptions) ([]Comparison, error) {
+	if len(weights) < 1 {
+		return nil, errors.New("too few items")
+	}
+	return compare(weights, opts.Draws, opts.Seed, true)
+}
+
+// compare measures every variant, checking their accuracy if check is set.
+func compare(weights []float64, draws int, seed int64, check bool) ([]Comparison, error) {
+	if draws < 1 {
+		draws = 1000000
+	}
+
+	var counts []uint64
+	if check {
+		counts = make([]uint64, len(weights))
+	}
+
+	var comparisons []Comparison
+	for _, v := range variants {
+		var c Comparison
+		r := &c.Result
+		r.Variant = v.name
+
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		gen, err := v.build(weights)
+		r.BuildTime = time.Since(start)
+		runtime.ReadMemStats(&after)
+		if err != nil {
+			return nil, errors.New(v.name + ": " + err.Error())
+		}
+		r.BuildBytes = after.TotalAlloc - before.TotalAlloc
+
+		cs := alias.NewCountingSource(rand.NewSource(seed))
+		rng := rand.New(cs)
+		start = time.Now()
+		if check {
+			clear(counts)
+			for i := 0; i < draws; i++ {
+				counts[gen(rng)]++
+			}
+		} else {
+			for i := 0; i < draws; i++ {
+				gen(rng)
+			}
+		}
+		elapsed := time.Since(start)
+
+		r.DrawsPerSecond = float64(draws) / elapsed.Seconds()
+		r.WordsPerDraw = float64(cs.Count()) / float64(draws)
+
+		if check {
+			c.TotalVariation, c.ChiSquare = accuracy(weights, counts, draws)
+		}
+
+		comparisons = append(comparisons, c)
+	}
+
+	return comparisons, nil
+}
+
+// accuracy compares the counts of draws with the weights.
+func accuracy(weights []float64, counts []uint64, draws int) (tv, chi2 float64) {
+	total := float64(0)
+	for _, w := range weights {
+		total += w
+	}
+
+	for i, w := range weights {
+		p := w / total
+		observed := float64(counts[i]) / float64(draws)
+		tv += math.Abs(observed - p)
+
+		expected := p * float64(draws)
+		d := float64(counts[i]) - expected
+		chi2 += d * d / expected
+	}
+	return tv / 2, chi2
+}
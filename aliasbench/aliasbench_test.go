@@ -0,0 +1,62 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package aliasbench
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	results, err := Run(Config{
+		N:       1000,
+		Weights: func(i int) float64 { return 1 / float64(i+1) },
+		Draws:   10000,
+	})
+	if err != nil {
+		t.Fatalf("Couldn't Run: %v", err)
+	}
+
+	if len(results) != len(variants) {
+		t.Fatalf("Got %v results, wanted %v", len(results), len(variants))
+	}
+	for _, r := range results {
+		if r.BuildBytes == 0 || !(r.DrawsPerSecond > 0) {
+			t.Errorf("Implausible result %+v", r)
+		}
+		if r.Variant != "NewHybrid" && r.WordsPerDraw != 1 {
+			t.Errorf("%v took %v words per draw, wanted 1", r.Variant, r.WordsPerDraw)
+		}
+	}
+
+	if _, err := Run(Config{N: 0, Weights: func(int) float64 { return 1 }}); err == nil {
+		t.Errorf("Run with no items did not fail")
+	}
+}
+
+func TestCompareSamplers(t *testing.T) {
+	weights := []float64{5, 1, 2, 8, 3, 1, 1, 4, 2, 9, 1, 6, 2, 7, 3, 1, 2, 5}
+	comparisons, err := CompareSamplers(weights, CompareOptions{Draws: 200000, Seed: 1})
+	if err != nil {
+		t.Fatalf("Couldn't CompareSamplers: %v", err)
+	}
+
+	if len(comparisons) != len(variants) {
+		t.Fatalf("Got %v comparisons, wanted %v", len(comparisons), len(variants))
+	}
+	for _, c := range comparisons {
+		if !(c.DrawsPerSecond > 0) {
+			t.Errorf("Implausible result %+v", c)
+		}
+		// 17 degrees of freedom; 50 is far beyond chance
+		if c.TotalVariation > 0.01 || c.ChiSquare > 50 {
+			t.Errorf("%v draws don't match the weights: %+v", c.Variant, c)
+		}
+	}
+
+	if _, err := CompareSamplers(nil, CompareOptions{}); err == nil {
+		t.Errorf("CompareSamplers with no weights did not fail")
+	}
+	if _, err := CompareSamplers([]float64{1, -1}, CompareOptions{}); err == nil {
+		t.Errorf("CompareSamplers with a negative weight did not fail")
+	}
+}
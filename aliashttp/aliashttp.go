@@ -0,0 +1,144 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+// The aliashttp package serves snapshots of live samplers over HTTP, for
+// inspecting them from a debug port alongside expvar and pprof.
+//
+// Register each table to be inspected, and draw through the returned
+// Sampler so its draws are counted:
+//
+//	h := aliashttp.NewHandler()
+//	s := h.Register("backends", table)
+//	http.Handle("/debug/alias", h)
+//	...
+//	i := s.Gen(rng)
+//
+// A GET of the handler's path returns a JSON object with a member for each
+// registered sampler, or just the one named by the "name" query parameter.
+package aliashttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/encryptio/alias"
+)
+
+// Handler is an http.Handler serving snapshots of registered samplers.
+type Handler struct {
+	mu       sync.RWMutex
+	samplers map[string]*Sampler
+}
+
+// NewHandler returns a Handler with no samplers registered.
+func NewHandler() *Handler {
+	return &Handler{samplers: make(map[string]*Sampler)}
+}
+
+// Sampler draws from a table and counts the draws, for Handler to report.
+// It is safe for concurrent use, provided each goroutine uses its own rng.
+type Sampler struct {
+	al     *alias.Alias
+	counts []uint64
+}
+
+// Register adds al to the snapshots under name, replacing any sampler
+// already registered under it, and returns a Sampler to draw from al
+// through.
+func (h *Handler) Register(name string, al *alias.Alias) *Sampler {
+	s := &Sampler{
+		al:     al,
+		counts: make([]uint64, al.Len()),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.samplers[name] = s
+	return s
+}
+
+// Unregister removes the sampler registered under name.
+func (h *Handler) Unregister(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.samplers, name)
+}
+
+// Gen draws from the table and counts the draw.
+func (s *Sampler) Gen(rng *rand.Rand) uint32 {
+	i := s.al.Gen(rng)
+	atomic.AddUint64(&s.counts[i], 1)
+	return i
+}
+
+type snapshot struct {
+	Fingerprint  string          `json:"fingerprint"`
+	Draws        uint64          `json:"draws"`
+	Counts       []uint64        `json:"counts"`
+	Distribution json.RawMessage `json:"distribution"`
+}
+
+func (s *Sampler) snapshot() (snapshot, error) {
+	var dist bytes.Buffer
+	if err := s.al.ExportJSON(&dist); err != nil {
+		return snapshot{}, err
+	}
+
+	snap := snapshot{
+		Fingerprint:  s.al.Fingerprint(),
+		Counts:       make([]uint64, len(s.counts)),
+		Distribution: dist.Bytes(),
+	}
+	for i := range s.counts {
+		snap.Counts[i] = atomic.LoadUint64(&s.counts[i])
+		snap.Draws += snap.Counts[i]
+	}
+	return snap, nil
+}
+
+// ServeHTTP implements http.Handler. Each sampler's snapshot holds its
+// table's fingerprint, the number of draws made through it so far and how
+// many landed on each index, and its distribution as written by
+// alias.Alias.ExportJSON.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.mu.RLock()
+	samplers := make(map[string]*Sampler, len(h.samplers))
+	if name := r.URL.Query().Get("name"); name != "" {
+		if s, ok := h.samplers[name]; ok {
+			samplers[name] = s
+		}
+	} else {
+		for name, s := range h.samplers {
+			samplers[name] = s
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(samplers) == 0 && r.URL.Query().Get("name") != "" {
+		http.Error(w, "no such sampler", http.StatusNotFound)
+		return
+	}
+
+	out := make(map[string]snapshot, len(samplers))
+	for name, s := range samplers {
+		snap, err := s.snapshot()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out[name] = snap
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
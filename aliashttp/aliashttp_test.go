@@ -0,0 +1,77 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package aliashttp
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/encryptio/alias"
+)
+
+func TestHandler(t *testing.T) {
+	a, err := alias.New([]float64{1, 3})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	h := NewHandler()
+	s := h.Register("coin", a)
+	h.Register("other", a)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		s.Gen(rng)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/alias?name=coin", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Got status %v", rec.Code)
+	}
+
+	var out map[string]struct {
+		Fingerprint  string
+		Draws        uint64
+		Counts       []uint64
+		Distribution struct {
+			N             int
+			Probabilities []float64
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Couldn't decode response %q: %v", rec.Body.String(), err)
+	}
+
+	snap, ok := out["coin"]
+	if !ok || len(out) != 1 {
+		t.Fatalf("Response was %q", rec.Body.String())
+	}
+	if snap.Fingerprint != a.Fingerprint() {
+		t.Errorf("Fingerprint was %q, wanted %q", snap.Fingerprint, a.Fingerprint())
+	}
+	if snap.Draws != 100 || len(snap.Counts) != 2 || snap.Counts[0]+snap.Counts[1] != 100 {
+		t.Errorf("Draws was %v with counts %v", snap.Draws, snap.Counts)
+	}
+	if snap.Distribution.N != 2 || snap.Distribution.Probabilities[1] != 0.75 {
+		t.Errorf("Distribution was %+v", snap.Distribution)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/alias", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil || len(out) != 2 {
+		t.Errorf("Listing all samplers gave %q", rec.Body.String())
+	}
+
+	h.Unregister("coin")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/alias?name=coin", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Unregistered sampler gave status %v", rec.Code)
+	}
+}
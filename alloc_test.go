@@ -0,0 +1,43 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNoAllocs(t *testing.T) {
+	a, err := New([]float64{1000, 1, 3, 10, 5, 5})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	// compute the cached probabilities up front
+	a.Quantile(0)
+
+	key := []byte("key")
+	words := make([]uint64, 8)
+	dst := make([]uint32, 16)
+	tables := []*Alias{a, a, a}
+
+	funcs := map[string]func(){
+		"Gen":          func() { a.Gen(rng) },
+		"GenHash":      func() { a.GenHash(rng.Uint64()) },
+		"GenKey":       func() { a.GenKey(key, nil) },
+		"GenN":         func() { a.GenN(rng, dst) },
+		"GenFromWords": func() { a.GenFromWords(words, dst) },
+		"MultiGen":     func() { MultiGen(rng, tables, dst) },
+		"GenInRange":   func() { a.GenInRange(rng, 1, 3) },
+		"GenBelow":     func() { a.GenBelow(rng, 4) },
+		"Quantile":     func() { a.Quantile(rng.Float64()) },
+	}
+	for name, f := range funcs {
+		if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+			t.Errorf("%v made %v allocations per call", name, allocs)
+		}
+	}
+}
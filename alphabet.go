@@ -0,0 +1,53 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"unicode/utf8"
+)
+
+// Alphabet picks runes from a weighted alphabet, for fuzzers and synthetic
+// text generators.
+type Alphabet struct {
+	al    *Alias
+	runes []rune
+}
+
+// NewAlphabet returns an Alphabet picking runes[i] with probability
+// proportional to weights[i]. Weights must be positive, as with New.
+func NewAlphabet(runes []rune, weights []float64) (*Alphabet, error) {
+	if len(runes) != len(weights) {
+		return nil, errors.New("runes and weights have different lengths")
+	}
+
+	al, err := New(weights)
+	if err != nil {
+		return nil, err
+	}
+	return &Alphabet{al: al, runes: append([]rune(nil), runes...)}, nil
+}
+
+// Rune returns a random rune from the alphabet.
+func (a *Alphabet) Rune(rng *rand.Rand) rune {
+	return a.runes[a.al.Gen(rng)]
+}
+
+// String returns a string of n random runes from the alphabet.
+func (a *Alphabet) String(rng *rand.Rand, n int) string {
+	var b strings.Builder
+	b.Grow(n)
+	for i := 0; i < n; i++ {
+		r := a.Rune(rng)
+		if r < utf8.RuneSelf {
+			b.WriteByte(byte(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestAlphabet(t *testing.T) {
+	a, err := NewAlphabet([]rune{'a', 'é', '日'}, []float64{6, 3, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create Alphabet: %v", err)
+	}
+
+	index := map[rune]uint32{'a': 0, 'é': 1, '日': 2}
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		return index[a.Rune(rng)]
+	}, []float64{6, 3, 1}, 1)
+
+	s := a.String(rand.New(rand.NewSource(2)), 50)
+	if n := utf8.RuneCountInString(s); n != 50 {
+		t.Errorf("String(50) had %v runes", n)
+	}
+	for _, r := range s {
+		if _, ok := index[r]; !ok {
+			t.Errorf("String returned rune %q not in the alphabet", r)
+		}
+	}
+
+	if _, err := NewAlphabet([]rune{'a'}, []float64{1, 2}); err == nil {
+		t.Errorf("NewAlphabet with mismatched lengths did not fail")
+	}
+}
@@ -85,3 +85,93 @@ func BenchmarkCreate5000(b *testing.B) {
 func BenchmarkCreate50000(b *testing.B) {
 	benchCreationSize(b, 50000)
 }
+
+func BenchmarkMultiGen1000(b *testing.B) {
+	tables := make([]*Alias, 1000)
+	for i := range tables {
+		arr := make([]float64, 50)
+		for j := range arr {
+			arr[j] = rand.Float64()
+		}
+		a, err := New(arr)
+		if err != nil {
+			b.Fatal("Got an error during creation:", err)
+		}
+		tables[i] = a
+	}
+
+	rng := rand.New(rand.NewSource(99))
+	out := make([]uint32, len(tables))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		MultiGen(rng, tables, out)
+	}
+}
+
+func BenchmarkGenN50(b *testing.B) {
+	arr := make([]float64, 50)
+	for i := range arr {
+		arr[i] = rand.Float64()
+	}
+	a, err := New(arr)
+	if err != nil {
+		b.Fatal("Got an error during creation:", err)
+	}
+
+	rng := rand.New(rand.NewSource(99))
+	dst := make([]uint32, 1000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		a.GenN(rng, dst)
+	}
+}
+
+func benchCreationIntSize(b *testing.B, size int) {
+	b.StopTimer()
+
+	arr := make([]uint64, size)
+	for i := 0; i < size; i++ {
+		arr[i] = uint64(rand.Int31()) + 1
+	}
+
+	b.StartTimer()
+
+	for i := 0; i < b.N; i++ {
+		NewInt(arr)
+	}
+}
+
+func BenchmarkCreateInt5(b *testing.B) {
+	benchCreationIntSize(b, 5)
+}
+
+func BenchmarkCreateInt500(b *testing.B) {
+	benchCreationIntSize(b, 500)
+}
+
+func BenchmarkCreateInt50000(b *testing.B) {
+	benchCreationIntSize(b, 50000)
+}
+
+func BenchmarkDynamicUpdate500k(b *testing.B) {
+	rng := rand.New(rand.NewSource(99))
+	weights := make([]float64, 500000)
+	for i := range weights {
+		weights[i] = rng.Float64()
+	}
+	d, err := NewDynamic(weights)
+	if err != nil {
+		b.Fatal("Got an error during creation:", err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.UpdateWeight(uint32(rng.Intn(len(weights))), rng.Float64())
+		d.Gen(rng)
+	}
+}
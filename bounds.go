@@ -0,0 +1,91 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"sort"
+)
+
+// boundProbs returns prob normalized and then clamped to [min, max], with
+// the unclamped items scaled together to keep the total at 1. A max of zero
+// means no cap.
+//
+// The result has the form clamp(prob[i]*s, min, max) for a single scale s.
+// The total is nondecreasing and piecewise linear in s, changing slope only
+// where some item reaches min or max, so s is found exactly by a binary
+// search over those breakpoints and interpolation between them.
+func boundProbs(prob []float64, min, max float64) ([]float64, error) {
+	n := len(prob)
+	if max <= 0 {
+		max = 1
+	}
+	if min > max {
+		return nil, errors.New("MinProb is larger than MaxProb")
+	}
+	if min*float64(n) > 1 {
+		return nil, errors.New("MinProb is too large for the number of items")
+	}
+	if max*float64(n) < 1 {
+		return nil, errors.New("MaxProb is too small for the number of items")
+	}
+
+	for _, v := range prob {
+		if v <= 0 {
+			return nil, errors.New("a probability is non-positive")
+		}
+	}
+
+	clamp := func(p float64) float64 {
+		if p < min {
+			return min
+		}
+		if p > max {
+			return max
+		}
+		return p
+	}
+
+	total := func(s float64) float64 {
+		t := float64(0)
+		for _, v := range prob {
+			t += clamp(v * s)
+		}
+		return t
+	}
+
+	breaks := make([]float64, 0, 2*n)
+	for _, v := range prob {
+		breaks = append(breaks, min/v, max/v)
+	}
+	sort.Float64s(breaks)
+
+	// past the last breakpoint every item is at max, so the total is at
+	// least 1 there; but when max*n is exactly 1, rounding in the sum can
+	// leave it just short, so the search must not run off the end
+	k := sort.Search(len(breaks), func(k int) bool {
+		return total(breaks[k]) >= 1
+	})
+	if k == len(breaks) {
+		k--
+	}
+
+	hi := breaks[k]
+	lo := float64(0)
+	if k > 0 {
+		lo = breaks[k-1]
+	}
+
+	s := hi
+	if tLo, tHi := total(lo), total(hi); tHi > tLo {
+		s = lo + (1-tLo)*(hi-lo)/(tHi-tLo)
+	}
+
+	out := make([]float64, n)
+	for i, v := range prob {
+		out[i] = clamp(v * s)
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMinProb(t *testing.T) {
+	a, err := NewWithOptions([]float64{1, 1000, 10, 989}, Options{MinProb: 0.1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	want := []float64{0.1, 0.8 * 1000 / 1989, 0.1, 0.8 * 989 / 1989}
+	for i, w := range want {
+		if p := a.Prob(uint32(i)); math.Abs(p-w) > 1e-8 {
+			t.Errorf("Prob(%v) was %v, wanted %v", i, p, w)
+		}
+	}
+
+	if _, err := NewWithOptions([]float64{1, 1, 1}, Options{MinProb: 0.4}); err == nil {
+		t.Errorf("NewWithOptions with an impossible MinProb did not fail")
+	}
+}
+
+func TestMaxProb(t *testing.T) {
+	tests := []struct {
+		prob     []float64
+		min, max float64
+		want     []float64
+	}{
+		{[]float64{6, 2, 1, 1}, 0, 0.4, []float64{0.4, 0.3, 0.15, 0.15}},
+		{[]float64{6, 2, 1, 1}, 0, 0.25, []float64{0.25, 0.25, 0.25, 0.25}},
+		{[]float64{100, 10, 1, 1}, 0.1, 0.5, []float64{0.5, 0.3, 0.1, 0.1}},
+
+		// MaxProb of exactly 1/n, where rounding leaves the total at the
+		// last breakpoint just short of 1
+		{
+			[]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 0, 0.1,
+			[]float64{0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1},
+		},
+	}
+	for _, test := range tests {
+		a, err := NewWithOptions(test.prob, Options{MinProb: test.min, MaxProb: test.max})
+		if err != nil {
+			t.Fatalf("Couldn't create alias: %v", err)
+		}
+
+		for i, w := range test.want {
+			if p := a.Prob(uint32(i)); math.Abs(p-w) > 1e-8 {
+				t.Errorf("Prob(%v) for %v in [%v, %v] was %v, wanted %v",
+					i, test.prob, test.min, test.max, p, w)
+			}
+		}
+	}
+
+	if _, err := NewWithOptions([]float64{1, 1, 1}, Options{MaxProb: 0.3}); err == nil {
+		t.Errorf("NewWithOptions with an impossible MaxProb did not fail")
+	}
+}
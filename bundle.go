@@ -0,0 +1,191 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sort"
+)
+
+// bundleMagic starts every bundle file.
+var bundleMagic = [4]byte{'A', 'L', 'B', 'N'}
+
+// WriteBundle writes many named tables to w as a single bundle, which can be
+// read back with OpenBundle or ReadBundle.
+//
+// A bundle is the magic bytes "ALBN", the number of tables as a little
+// endian uint32, then an index with one entry per table in name order: the
+// name's length as a little endian uint32, the name, and the offset and
+// length of the table's data as little endian uint64s. The tables follow,
+// each as written by MarshalBinary. Offsets are from the start of the
+// bundle.
+func WriteBundle(w io.Writer, tables map[string]*Alias) error {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	blobs := make([][]byte, len(names))
+	offset := uint64(8)
+	for i, name := range names {
+		var err error
+		blobs[i], err = tables[name].MarshalBinary()
+		if err != nil {
+			return err
+		}
+		offset += 4 + uint64(len(name)) + 16
+	}
+
+	index := make([]byte, 0, offset)
+	index = append(index, bundleMagic[:]...)
+	index = binary.LittleEndian.AppendUint32(index, uint32(len(names)))
+	for i, name := range names {
+		index = binary.LittleEndian.AppendUint32(index, uint32(len(name)))
+		index = append(index, name...)
+		index = binary.LittleEndian.AppendUint64(index, offset)
+		index = binary.LittleEndian.AppendUint64(index, uint64(len(blobs[i])))
+		offset += uint64(len(blobs[i]))
+	}
+
+	if _, err := w.Write(index); err != nil {
+		return err
+	}
+	for _, blob := range blobs {
+		if _, err := w.Write(blob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bundle is a set of named tables read from a bundle file. Only the index is
+// read up front; each table is read when it's asked for.
+type Bundle struct {
+	r     io.ReaderAt
+	c     io.Closer
+	index map[string]bundleEntry
+	names []string
+}
+
+type bundleEntry struct {
+	offset, length uint64
+}
+
+// OpenBundle opens the bundle file at path. The Bundle must be closed when
+// it's no longer needed; tables already returned by Get stay usable.
+func OpenBundle(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	b, err := ReadBundle(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	b.c = f
+	return b, nil
+}
+
+// ReadBundle reads the index of a bundle of the given size from r.
+func ReadBundle(r io.ReaderAt, size int64) (*Bundle, error) {
+	sr := io.NewSectionReader(r, 0, size)
+
+	var head [8]byte
+	if _, err := io.ReadFull(sr, head[:]); err != nil {
+		return nil, errors.New("bad bundle: short header")
+	}
+	if [4]byte(head[:4]) != bundleMagic {
+		return nil, errors.New("bad bundle: wrong magic")
+	}
+	n := binary.LittleEndian.Uint32(head[4:])
+
+	// every entry takes at least 20 bytes
+	if uint64(n)*20 > uint64(size) {
+		return nil, errors.New("bad bundle: too many entries")
+	}
+
+	b := &Bundle{
+		r:     r,
+		index: make(map[string]bundleEntry, n),
+		names: make([]string, 0, n),
+	}
+	for i := uint32(0); i < n; i++ {
+		var l [4]byte
+		if _, err := io.ReadFull(sr, l[:]); err != nil {
+			return nil, errors.New("bad bundle: short index")
+		}
+		nameLen := binary.LittleEndian.Uint32(l[:])
+		if uint64(nameLen) > uint64(size) {
+			return nil, errors.New("bad bundle: short index")
+		}
+
+		entry := make([]byte, int(nameLen)+16)
+		if _, err := io.ReadFull(sr, entry); err != nil {
+			return nil, errors.New("bad bundle: short index")
+		}
+		name := string(entry[:nameLen])
+		e := bundleEntry{
+			offset: binary.LittleEndian.Uint64(entry[nameLen:]),
+			length: binary.LittleEndian.Uint64(entry[nameLen+8:]),
+		}
+
+		if e.offset > uint64(size) || e.length > uint64(size)-e.offset {
+			return nil, errors.New("bad bundle: table out of range")
+		}
+		if _, dup := b.index[name]; dup {
+			return nil, errors.New("bad bundle: duplicate name " + name)
+		}
+
+		b.index[name] = e
+		b.names = append(b.names, name)
+	}
+
+	return b, nil
+}
+
+// Names returns the names of the tables in the bundle, in order.
+func (b *Bundle) Names() []string {
+	return append([]string(nil), b.names...)
+}
+
+// Get reads and returns the named table.
+func (b *Bundle) Get(name string) (*Alias, error) {
+	e, ok := b.index[name]
+	if !ok {
+		return nil, errors.New("no table named " + name)
+	}
+
+	data := make([]byte, e.length)
+	if _, err := b.r.ReadAt(data, int64(e.offset)); err != nil {
+		return nil, err
+	}
+
+	al := &Alias{}
+	if err := al.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return al, nil
+}
+
+// Close closes the file opened by OpenBundle. It does nothing for a Bundle
+// from ReadBundle.
+func (b *Bundle) Close() error {
+	if b.c == nil {
+		return nil
+	}
+	return b.c.Close()
+}
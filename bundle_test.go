@@ -0,0 +1,69 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	tables := make(map[string]*Alias)
+	for name, dist := range map[string][]float64{
+		"us": {1, 2, 3},
+		"de": {1},
+		"fr": {9, 8, 1, 4, 2},
+	} {
+		a, err := New(dist)
+		if err != nil {
+			t.Fatalf("Couldn't create alias: %v", err)
+		}
+		tables[name] = a
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, tables); err != nil {
+		t.Fatalf("Couldn't WriteBundle: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "bundle")
+	if err := os.WriteFile(path, buf.Bytes(), 0666); err != nil {
+		t.Fatalf("Couldn't write bundle: %v", err)
+	}
+
+	b, err := OpenBundle(path)
+	if err != nil {
+		t.Fatalf("Couldn't OpenBundle: %v", err)
+	}
+	defer b.Close()
+
+	if names := b.Names(); !reflect.DeepEqual(names, []string{"de", "fr", "us"}) {
+		t.Errorf("Names was %v", names)
+	}
+
+	for name, want := range tables {
+		got, err := b.Get(name)
+		if err != nil {
+			t.Fatalf("Couldn't Get(%q): %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Get(%q) returned a different table", name)
+		}
+	}
+
+	if _, err := b.Get("jp"); err == nil {
+		t.Errorf("Get of a missing table did not fail")
+	}
+
+	data := buf.Bytes()
+	for i := 0; i < len(data); i++ {
+		if _, err := ReadBundle(bytes.NewReader(data[:i]), int64(i)); err == nil {
+			t.Errorf("ReadBundle of a bundle truncated to %v bytes did not fail", i)
+		}
+	}
+}
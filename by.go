@@ -0,0 +1,74 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// By picks elements of a slice, weighted by a function of each element.
+type By[T any] struct {
+	al    *Alias
+	items []T
+}
+
+// Create a new By over items, using weight to find each item's weight. For
+// example,
+//
+//	type option struct {
+//		Name   string
+//		Weight float64
+//	}
+//	b, err := alias.NewBy(options, func(o option) float64 { return o.Weight })
+//
+// The weights must be positive, as with New. The items slice is not copied,
+// and Pick returns pointers into it.
+func NewBy[T any](items []T, weight func(T) float64) (*By[T], error) {
+	prob := make([]float64, len(items))
+	for i, item := range items {
+		prob[i] = weight(item)
+	}
+
+	al, err := New(prob)
+	if err != nil {
+		return nil, err
+	}
+
+	return &By[T]{al: al, items: items}, nil
+}
+
+// Pick returns a pointer to a random element of the slice passed to NewBy,
+// chosen according to the weights.
+func (b *By[T]) Pick(rng *rand.Rand) *T {
+	return &b.items[b.al.Gen(rng)]
+}
+
+// Of picks values of type T, with the table and the values kept together so
+// they can't get out of step when a distribution is rebuilt.
+type Of[T any] struct {
+	al    *Alias
+	items []T
+}
+
+// Create a new Of picking items[i] with probability proportional to
+// weights[i]. The weights must be positive, as with New. items is copied.
+func NewOf[T any](items []T, weights []float64) (*Of[T], error) {
+	if len(items) != len(weights) {
+		return nil, errors.New("items and weights have different lengths")
+	}
+
+	al, err := New(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Of[T]{al: al, items: append([]T(nil), items...)}, nil
+}
+
+// Gen returns a random item, chosen according to the weights.
+func (o *Of[T]) Gen(rng *rand.Rand) T {
+	return o.items[o.al.Gen(rng)]
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBy(t *testing.T) {
+	type option struct {
+		Index  uint32
+		Weight float64
+	}
+	options := []option{{0, 3}, {1, 1}, {2, 6}}
+
+	b, err := NewBy(options, func(o option) float64 { return o.Weight })
+	if err != nil {
+		t.Fatalf("Couldn't create By: %v", err)
+	}
+
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		return b.Pick(rng).Index
+	}, []float64{3, 1, 6}, 1)
+
+	if _, err := NewBy(options, func(o option) float64 { return o.Weight - 1 }); err == nil {
+		t.Errorf("NewBy with a zero weight did not fail")
+	}
+}
+
+func TestOf(t *testing.T) {
+	items := []string{"low", "mid", "high"}
+	o, err := NewOf(items, []float64{3, 1, 6})
+	if err != nil {
+		t.Fatalf("Couldn't create Of: %v", err)
+	}
+
+	// rebuilding the caller's slice doesn't affect o
+	items[0] = "changed"
+
+	index := map[string]uint32{"low": 0, "mid": 1, "high": 2}
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		v, ok := index[o.Gen(rng)]
+		if !ok {
+			t.Fatalf("Gen returned an unknown item")
+		}
+		return v
+	}, []float64{3, 1, 6}, 1)
+
+	if _, err := NewOf(items, []float64{1}); err == nil {
+		t.Errorf("NewOf with too few weights did not fail")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "errors"
+
+// Create a new alias object weighting items by their spare capacity, for
+// capacity-aware load balancing. Item i gets weight
+//
+//	capacity[i] * (1 - utilization[i])
+//
+// where utilization is the fraction of capacity in use. utilization may be
+// nil, meaning every item is idle; otherwise it must be as long as capacity.
+// Utilization is clamped to [0,1].
+//
+// Items with no spare capacity get weight zero, and are never picked. At
+// least one item must have spare capacity.
+//
+// To follow changing utilization, build a new table and swap it in.
+func NewCapacity(capacity, utilization []float64) (*Alias, error) {
+	if utilization != nil && len(utilization) != len(capacity) {
+		return nil, errors.New("capacity and utilization have different lengths")
+	}
+
+	prob := make([]float64, len(capacity))
+	for i, c := range capacity {
+		if c < 0 {
+			return nil, errors.New("a capacity is negative")
+		}
+
+		u := float64(0)
+		if utilization != nil {
+			u = utilization[i]
+		}
+		if u > 1 {
+			u = 1
+		} else if !(u > 0) {
+			u = 0
+		}
+
+		prob[i] = c * (1 - u)
+	}
+
+	al, _, err := build(prob, true)
+	if err != nil {
+		return nil, err
+	}
+	return al, nil
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "testing"
+
+func TestNewCapacity(t *testing.T) {
+	a, err := NewCapacity([]float64{10, 10, 20, 5}, []float64{0.5, 1, 0.75, 1.5})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	checkDistribution(t, a.Gen, []float64{5, 0, 5, 0}, 1)
+
+	a, err = NewCapacity([]float64{1, 3}, nil)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	checkDistribution(t, a.Gen, []float64{1, 3}, 2)
+
+	if _, err := NewCapacity([]float64{1, 1}, []float64{1, 1}); err == nil {
+		t.Errorf("NewCapacity with no spare capacity did not fail")
+	}
+}
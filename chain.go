@@ -0,0 +1,48 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "math/rand"
+
+// Chain picks from an ordered list of tables, moving on to the next table
+// when picks from one keep turning out to be unavailable. This is the usual
+// shape of weighted backend selection with fallbacks.
+type Chain struct {
+	Links []Link
+
+	// Tries is the number of draws made from each link before moving on to
+	// the next. Values less than 1 mean 1.
+	Tries int
+}
+
+// Link is one table in a Chain.
+type Link struct {
+	Table *Alias
+
+	// Available reports whether an index drawn from Table may be used. A nil
+	// Available accepts every index.
+	Available func(i uint32) bool
+}
+
+// Gen draws from each link in order, returning the position of the link in
+// Links and the first available index drawn from it. It returns false if no
+// link produced an available index within its tries.
+func (c *Chain) Gen(rng *rand.Rand) (link int, i uint32, ok bool) {
+	tries := c.Tries
+	if tries < 1 {
+		tries = 1
+	}
+
+	for link, l := range c.Links {
+		for try := 0; try < tries; try++ {
+			i := l.Table.Gen(rng)
+			if l.Available == nil || l.Available(i) {
+				return link, i, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
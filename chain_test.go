@@ -0,0 +1,52 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	primary, err := New([]float64{1, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	backup, err := New([]float64{1, 3})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	down := map[uint32]bool{0: true, 1: true}
+	c := &Chain{
+		Links: []Link{
+			{primary, func(i uint32) bool { return !down[i] }},
+			{backup, nil},
+		},
+		Tries: 3,
+	}
+
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		link, i, ok := c.Gen(rng)
+		if !ok || link != 1 {
+			t.Fatalf("Gen returned %v, %v, %v with the primary down", link, i, ok)
+		}
+		return i
+	}, []float64{1, 3}, 1)
+
+	down[1] = false
+	rng := rand.New(rand.NewSource(2))
+	for j := 0; j < 100; j++ {
+		if link, i, _ := c.Gen(rng); link == 0 && i != 1 {
+			t.Fatalf("Gen returned unavailable index %v", i)
+		}
+	}
+
+	c.Links[1].Available = func(uint32) bool { return false }
+	down[1] = true
+	if _, _, ok := c.Gen(rng); ok {
+		t.Errorf("Gen succeeded with nothing available")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "errors"
+
+// Columns returns the table as two columns, for analysis by numerical code:
+// thresholds[w] is slot w's threshold in [0,2^31), and aliases[w] its alias
+// target, as described for Layout. The slices are copies; changing them
+// doesn't change the table.
+func (al *Alias) Columns() (thresholds, aliases []uint32) {
+	thresholds = make([]uint32, len(al.table))
+	aliases = make([]uint32, len(al.table))
+	for w, piece := range al.table {
+		thresholds[w] = piece.prob
+		aliases[w] = piece.alias
+	}
+	return thresholds, aliases
+}
+
+// SlotKeep returns, for each slot, the fraction of its draws that return
+// the slot's own index rather than its alias target, in float32 for
+// compactness. This is the threshold column normalized, and exact but for
+// the conversion to float32.
+func (al *Alias) SlotKeep() []float32 {
+	keep := make([]float32, len(al.table))
+	for w := range al.table {
+		direct, total := slotCounts(al.table, uint32(w))
+		if total > 0 {
+			keep[w] = float32(float64(direct) / float64(total))
+		}
+	}
+	return keep
+}
+
+// Probs returns a copy of the probability the table gives each index, as
+// returned by Prob.
+func (al *Alias) Probs() []float64 {
+	return append([]float64(nil), al.pmf()...)
+}
+
+// FromColumns builds a table from columns as returned by Columns, copying
+// them, so tables edited or generated by numerical code can be imported.
+func FromColumns(thresholds, aliases []uint32) (*Alias, error) {
+	if len(thresholds) != len(aliases) {
+		return nil, errors.New("columns have different lengths")
+	}
+
+	static := make([]uint32, 0, 2*len(thresholds))
+	for w := range thresholds {
+		static = append(static, thresholds[w], aliases[w])
+	}
+	return FromStatic(static)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumns(t *testing.T) {
+	a := mustNew(t, []float64{1, 3})
+
+	thresholds, aliases := a.Columns()
+	if !reflect.DeepEqual(thresholds, []uint32{1<<30 - 1, 1<<31 - 1}) || !reflect.DeepEqual(aliases, []uint32{1, 0}) {
+		t.Errorf("Columns were %v, %v", thresholds, aliases)
+	}
+
+	// copies, not views
+	thresholds[0] = 0
+	if a.table[0].prob == 0 {
+		t.Errorf("Changing Columns changed the table")
+	}
+	thresholds[0] = 1<<30 - 1
+
+	if keep := a.SlotKeep(); !reflect.DeepEqual(keep, []float32{0.5, 1}) {
+		t.Errorf("SlotKeep was %v, wanted [0.5 1]", keep)
+	}
+	if probs := a.Probs(); !reflect.DeepEqual(probs, []float64{0.25, 0.75}) {
+		t.Errorf("Probs was %v, wanted [0.25 0.75]", probs)
+	}
+
+	b, err := FromColumns(thresholds, aliases)
+	if err != nil {
+		t.Fatalf("Couldn't FromColumns: %v", err)
+	}
+	if !reflect.DeepEqual(b.table, a.table) {
+		t.Errorf("FromColumns table was %v, wanted %v", b.table, a.table)
+	}
+
+	if _, err := FromColumns(thresholds, aliases[:1]); err == nil {
+		t.Errorf("FromColumns with mismatched columns did not fail")
+	}
+	if _, err := FromColumns([]uint32{0}, []uint32{1}); err == nil {
+		t.Errorf("FromColumns with a bad alias did not fail")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+)
+
+// Combination gives constraints on the items GenCombination picks, in terms
+// of groups of indices, such as advertisers on a slate or roles on a team.
+type Combination struct {
+	// Groups gives the group of each index of the table. It may be nil if
+	// neither AtMostOne nor Require is used; otherwise it must be as long as
+	// the table.
+	Groups []uint32
+
+	// AtMostOne allows at most one pick from each group.
+	AtMostOne bool
+
+	// Require lists groups that must each have at least one pick.
+	Require []uint32
+
+	// Tries is the number of times to start over after running out of
+	// items that could satisfy the constraints. Values less than 1 mean 16.
+	Tries int
+}
+
+// GenCombination picks k distinct indices satisfying the constraints in c.
+//
+// Items are drawn one at a time, each according to the distribution
+// renormalized over the items still allowed, as by GenDistinct. Items in the
+// group of each pick are disallowed if c.AtMostOne is set. Once the picks
+// left are only just enough to cover the required groups not yet covered,
+// they're drawn from those groups alone. If the allowed items run out
+// first, the draw starts over, up to c.Tries times.
+//
+// Without Require, the result is exactly sequential weighted sampling
+// without replacement over the allowed items. Requiring groups makes the
+// result differ from conditioning an unconstrained draw on the constraints:
+// required groups are favored by being drawn from directly in the last
+// picks, and starting over favors combinations that are less likely to run
+// out of items. The bias is smallest when the required groups hold a large
+// share of the mass or k leaves plenty of room to cover them by chance.
+func (al *Alias) GenCombination(rng *rand.Rand, k int, c Combination) ([]uint32, error) {
+	return al.GenCombinationContext(context.Background(), rng, k, c)
+}
+
+// GenCombinationContext is GenCombination, but gives up with ctx.Err() if
+// ctx is done before it finishes. ctx is checked before each attempt.
+func (al *Alias) GenCombinationContext(ctx context.Context, rng *rand.Rand, k int, c Combination) ([]uint32, error) {
+	if (c.Groups != nil || c.AtMostOne || len(c.Require) > 0) && len(c.Groups) != len(al.table) {
+		return nil, errors.New("wrong number of groups")
+	}
+
+	required := make(map[uint32]bool)
+	for _, g := range c.Require {
+		required[g] = true
+	}
+	if len(required) > k {
+		return nil, errors.New("more groups required than items picked")
+	}
+
+	members := make(map[uint32][]uint32)
+	if c.AtMostOne {
+		for i, g := range c.Groups {
+			members[g] = append(members[g], uint32(i))
+		}
+	}
+
+	tries := c.Tries
+	if tries < 1 {
+		tries = 16
+	}
+
+	for try := 0; try < tries; try++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if out := al.tryCombination(rng, k, c, required, members); out != nil {
+			return out, nil
+		}
+	}
+	return nil, errors.New("no combination found")
+}
+
+// tryCombination makes one attempt for GenCombination, returning nil if it
+// runs out of allowed items.
+func (al *Alias) tryCombination(rng *rand.Rand, k int, c Combination, required map[uint32]bool, members map[uint32][]uint32) []uint32 {
+	d := al.BeginDraw()
+	uncovered := make(map[uint32]bool, len(required))
+	for g := range required {
+		uncovered[g] = true
+	}
+
+	out := make([]uint32, 0, k)
+	for len(out) < k {
+		var i uint32
+		if len(uncovered) == k-len(out) {
+			allowed := func(i int, p float64) float64 {
+				if d.excluded[uint32(i)] || !uncovered[c.Groups[i]] {
+					return 0
+				}
+				return p
+			}
+			if !anyAllowed(d.pmf, allowed) {
+				return nil
+			}
+			i = scan(rng, d.pmf, allowed)
+		} else {
+			var ok bool
+			i, ok = d.peek(rng)
+			if !ok {
+				return nil
+			}
+		}
+
+		out = append(out, i)
+		d.Exclude(i)
+		if c.Groups != nil {
+			g := c.Groups[i]
+			delete(uncovered, g)
+			for _, j := range members[g] {
+				d.Exclude(j)
+			}
+		}
+	}
+	return out
+}
+
+func anyAllowed(pmf []float64, weight func(i int, p float64) float64) bool {
+	for i, p := range pmf {
+		if weight(i, p) > 0 {
+			return true
+		}
+	}
+	return false
+}
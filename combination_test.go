@@ -0,0 +1,70 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestGenCombination(t *testing.T) {
+	a := mustNew(t, []float64{8, 4, 2, 1, 1})
+	groups := []uint32{0, 0, 1, 1, 2}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		out, err := a.GenCombination(rng, 2, Combination{
+			Groups:    groups,
+			AtMostOne: true,
+			Require:   []uint32{2},
+		})
+		if err != nil {
+			t.Fatalf("Couldn't GenCombination: %v", err)
+		}
+		if len(out) != 2 {
+			t.Fatalf("GenCombination returned %v, wanted 2 items", out)
+		}
+		if groups[out[0]] == groups[out[1]] {
+			t.Fatalf("GenCombination returned two from one group: %v", out)
+		}
+		if out[0] != 4 && out[1] != 4 {
+			t.Fatalf("GenCombination left group 2 uncovered: %v", out)
+		}
+	}
+
+	// with no constraints, the first pick follows the distribution
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		out, err := a.GenCombination(rng, 3, Combination{})
+		if err != nil {
+			t.Fatalf("Couldn't GenCombination: %v", err)
+		}
+		return out[0]
+	}, []float64{8, 4, 2, 1, 1}, 2)
+
+	// one per group leaves only three items
+	if _, err := a.GenCombination(rng, 4, Combination{Groups: groups, AtMostOne: true}); err == nil {
+		t.Errorf("GenCombination of too many items did not fail")
+	}
+	if _, err := a.GenCombination(rng, 1, Combination{Groups: groups, Require: []uint32{0, 1}}); err == nil {
+		t.Errorf("GenCombination requiring too many groups did not fail")
+	}
+	if _, err := a.GenCombination(rng, 1, Combination{Groups: groups[:2], AtMostOne: true}); err == nil {
+		t.Errorf("GenCombination with too few groups did not fail")
+	}
+	if _, err := a.GenCombination(rng, 3, Combination{Groups: groups[:2]}); err == nil {
+		t.Errorf("GenCombination with too few unused groups did not fail")
+	}
+}
+
+func TestGenCombinationContext(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.GenCombinationContext(ctx, rand.New(rand.NewSource(1)), 2, Combination{}); err != context.Canceled {
+		t.Errorf("GenCombinationContext after cancel returned %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math"
+)
+
+// fromPMF builds a table from a probability mass function, which may include
+// zeros.
+func fromPMF(pmf []float64) (*Alias, error) {
+	al, _, err := build(pmf, true)
+	return al, err
+}
+
+// Blend returns a table for the mixture (1-t)*a + t*b of two tables'
+// distributions, built from their encoded probabilities rather than the
+// original weights. If the tables have different sizes, the smaller one
+// gives probability zero to the extra indices. t must be in [0,1].
+//
+// Blending in steps from 0 to 1 gives a smooth transition between two
+// distributions.
+func Blend(a, b *Alias, t float64) (*Alias, error) {
+	if !(t >= 0 && t <= 1) {
+		return nil, errors.New("blend factor out of range")
+	}
+
+	pa, pb := a.pmf(), b.pmf()
+	n := len(pa)
+	if len(pb) > n {
+		n = len(pb)
+	}
+
+	pmf := make([]float64, n)
+	for i, p := range pa {
+		pmf[i] += (1 - t) * p
+	}
+	for i, p := range pb {
+		pmf[i] += t * p
+	}
+
+	return fromPMF(pmf)
+}
+
+// Divergence compares two tables' encoded distributions, such as a new table
+// and the one it would replace. kl is the Kullback-Leibler divergence of b
+// from a, in nats, which is infinite if b gives zero probability to an index
+// a can return. tv is the total variation distance, the largest difference
+// in probability the two give any set of indices, from 0 to 1. Indices
+// beyond the end of a table have probability zero in it.
+func Divergence(a, b *Alias) (kl, tv float64) {
+	pa, pb := a.pmf(), b.pmf()
+
+	at := func(pmf []float64, i int) float64 {
+		if i < len(pmf) {
+			return pmf[i]
+		}
+		return 0
+	}
+
+	n := len(pa)
+	if len(pb) > n {
+		n = len(pb)
+	}
+
+	for i := 0; i < n; i++ {
+		p, q := at(pa, i), at(pb, i)
+		tv += math.Abs(p - q)
+		if p > 0 {
+			kl += p * math.Log(p/q)
+		}
+	}
+
+	return kl, tv / 2
+}
+
+// Convolve returns a table for the outcome combine(i, j), where i is drawn
+// from a and j independently from b, such as the sum of two weighted dice.
+// The result has one index past the largest combine returns. Only pairs
+// with nonzero probability are combined, in O(len(a)*len(b)) time.
+func Convolve(a, b *Alias, combine func(i, j uint32) uint32) (*Alias, error) {
+	pa, pb := a.pmf(), b.pmf()
+
+	var pmf []float64
+	for i, p := range pa {
+		if p <= 0 {
+			continue
+		}
+		for j, q := range pb {
+			if q <= 0 {
+				continue
+			}
+
+			k := combine(uint32(i), uint32(j))
+			if k == math.MaxUint32 {
+				return nil, errors.New("too many probabilities")
+			}
+			if int(k) >= len(pmf) {
+				pmf = append(pmf, make([]float64, int(k)+1-len(pmf))...)
+			}
+			pmf[k] += p * q
+		}
+	}
+
+	return fromPMF(pmf)
+}
+
+// Remap returns a table that gives index perm[i] the probability al gives
+// index i, for item IDs that were re-keyed after the table was built. perm
+// must have one entry per index and no repeats. It may be a permutation, or
+// map into a larger space, in which case the result has one index past the
+// largest entry and the indices nothing maps to get probability zero.
+//
+// The table is built from al's encoded probabilities, not the original
+// weights, in O(n) time.
+func (al *Alias) Remap(perm []uint32) (*Alias, error) {
+	pa := al.pmf()
+	if len(perm) != len(pa) {
+		return nil, errors.New("remapping has the wrong length")
+	}
+
+	n := 0
+	for _, k := range perm {
+		if k == math.MaxUint32 {
+			return nil, errors.New("too many probabilities")
+		}
+		if int(k) >= n {
+			n = int(k) + 1
+		}
+	}
+
+	pmf := make([]float64, n)
+	used := make([]bool, n)
+	for i, k := range perm {
+		if used[k] {
+			return nil, errors.New("remapping repeats an index")
+		}
+		used[k] = true
+		pmf[k] = pa[i]
+	}
+
+	return fromPMF(pmf)
+}
+
+// Merge returns a table over the disjoint union of two tables' indices, such
+// as two shards built separately: index i of a keeps index i, and index j of
+// b becomes index offsetB+j. The two tables' distributions are mixed in the
+// ratio wa to wb. offsetB must be at least len(a), and the result has
+// offsetB+len(b) indices, with any gap between the two getting probability
+// zero.
+//
+// Like Blend, it works from the tables' encoded probabilities, not the
+// original weights.
+func Merge(a, b *Alias, wa, wb float64, offsetB uint32) (*Alias, error) {
+	if !(wa >= 0 && wb >= 0 && wa+wb > 0) || math.IsInf(wa+wb, 0) {
+		return nil, errors.New("bad merge weights")
+	}
+
+	pa, pb := a.pmf(), b.pmf()
+	if int(offsetB) < len(pa) {
+		return nil, errors.New("merged tables overlap")
+	}
+	n := int(offsetB) + len(pb)
+	if int(uint32(n)) != n {
+		return nil, errors.New("too many probabilities")
+	}
+
+	ta, tb := wa/(wa+wb), wb/(wa+wb)
+	pmf := make([]float64, n)
+	for i, p := range pa {
+		pmf[i] = ta * p
+	}
+	for j, p := range pb {
+		pmf[int(offsetB)+j] = tb * p
+	}
+
+	return fromPMF(pmf)
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"testing"
+)
+
+func mustNew(t *testing.T, prob []float64) *Alias {
+	a, err := New(prob)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	return a
+}
+
+func checkProbs(t *testing.T, a *Alias, want []float64) {
+	if len(a.table) != len(want) {
+		t.Errorf("Table has %v entries, wanted %v", len(a.table), len(want))
+	}
+	for i, w := range want {
+		if p := a.Prob(uint32(i)); math.Abs(p-w) > 1e-8 {
+			t.Errorf("Prob(%v) was %v, wanted %v", i, p, w)
+		}
+	}
+}
+
+func TestBlend(t *testing.T) {
+	a := mustNew(t, []float64{1, 1})
+	b := mustNew(t, []float64{1, 1, 2})
+
+	c, err := Blend(a, b, 0.5)
+	if err != nil {
+		t.Fatalf("Couldn't blend: %v", err)
+	}
+	checkProbs(t, c, []float64{0.375, 0.375, 0.25})
+
+	c, err = Blend(a, b, 0)
+	if err != nil {
+		t.Fatalf("Couldn't blend: %v", err)
+	}
+	checkDistribution(t, c.Gen, []float64{1, 1, 0}, 1)
+
+	if _, err := Blend(a, b, 2); err == nil {
+		t.Errorf("Blend with t = 2 did not fail")
+	}
+}
+
+func TestDivergence(t *testing.T) {
+	a := mustNew(t, []float64{1, 1})
+	b := mustNew(t, []float64{1, 3})
+	c := mustNew(t, []float64{1, 1, 2})
+
+	kl, tv := Divergence(a, a)
+	if kl != 0 || tv != 0 {
+		t.Errorf("Divergence(a, a) was %v, %v", kl, tv)
+	}
+
+	kl, tv = Divergence(a, b)
+	wantKL := 0.5*math.Log(0.5/0.25) + 0.5*math.Log(0.5/0.75)
+	if math.Abs(kl-wantKL) > 1e-8 || math.Abs(tv-0.25) > 1e-8 {
+		t.Errorf("Divergence(a, b) was %v, %v; wanted %v, 0.25", kl, tv, wantKL)
+	}
+
+	kl, tv = Divergence(c, a)
+	if !math.IsInf(kl, 1) || math.Abs(tv-0.5) > 1e-8 {
+		t.Errorf("Divergence(c, a) was %v, %v; wanted +Inf, 0.5", kl, tv)
+	}
+}
+
+func TestConvolve(t *testing.T) {
+	// a fair coin and a die loaded toward 2
+	coin := mustNew(t, []float64{1, 1})
+	die := mustNew(t, []float64{1, 2, 1})
+
+	c, err := Convolve(coin, die, func(i, j uint32) uint32 { return i + j })
+	if err != nil {
+		t.Fatalf("Couldn't convolve: %v", err)
+	}
+	checkProbs(t, c, []float64{0.125, 0.375, 0.375, 0.125})
+
+	// indices no pair produces get zero
+	c, err = Convolve(coin, coin, func(i, j uint32) uint32 { return 2 * (i + j) })
+	if err != nil {
+		t.Fatalf("Couldn't convolve: %v", err)
+	}
+	checkProbs(t, c, []float64{0.25, 0, 0.5, 0, 0.25})
+
+	if _, err := Convolve(coin, coin, func(i, j uint32) uint32 { return math.MaxUint32 }); err == nil {
+		t.Errorf("Convolve to too many indices did not fail")
+	}
+}
+
+func TestRemap(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 5})
+
+	r, err := a.Remap([]uint32{2, 0, 1})
+	if err != nil {
+		t.Fatalf("Couldn't remap: %v", err)
+	}
+	checkProbs(t, r, []float64{0.25, 0.625, 0.125})
+
+	// into a larger space
+	r, err = a.Remap([]uint32{0, 4, 2})
+	if err != nil {
+		t.Fatalf("Couldn't remap: %v", err)
+	}
+	checkProbs(t, r, []float64{0.125, 0, 0.625, 0, 0.25})
+
+	if _, err := a.Remap([]uint32{0, 1}); err == nil {
+		t.Errorf("Remap with too few entries did not fail")
+	}
+	if _, err := a.Remap([]uint32{0, 2, 2}); err == nil {
+		t.Errorf("Remap with a repeated index did not fail")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := mustNew(t, []float64{1, 3})
+	b := mustNew(t, []float64{1, 1})
+
+	m, err := Merge(a, b, 1, 1, 2)
+	if err != nil {
+		t.Fatalf("Couldn't merge: %v", err)
+	}
+	checkProbs(t, m, []float64{0.125, 0.375, 0.25, 0.25})
+
+	// a gap between the tables, and uneven weights
+	m, err = Merge(a, b, 1, 3, 3)
+	if err != nil {
+		t.Fatalf("Couldn't merge: %v", err)
+	}
+	checkProbs(t, m, []float64{0.0625, 0.1875, 0, 0.375, 0.375})
+
+	if _, err := Merge(a, b, 1, 1, 1); err == nil {
+		t.Errorf("Merge of overlapping tables did not fail")
+	}
+	if _, err := Merge(a, b, 0, 0, 2); err == nil {
+		t.Errorf("Merge with zero weights did not fail")
+	}
+	if _, err := Merge(a, b, -1, 2, 2); err == nil {
+		t.Errorf("Merge with a negative weight did not fail")
+	}
+}
+
+func TestRemapUnmapped(t *testing.T) {
+	a := mustNew(t, []float64{1, 3})
+	r, err := a.Remap([]uint32{1, 2})
+	if err != nil {
+		t.Fatalf("Couldn't remap: %v", err)
+	}
+
+	if p := r.Prob(0); p != 0 {
+		t.Errorf("Unmapped index 0 has probability %v", p)
+	}
+	for ri := uint32(0); ri < 1000; ri++ {
+		if v := r.genFrom(ri); v == 0 {
+			t.Fatalf("genFrom(%v) returned unmapped index 0", ri)
+		}
+	}
+}
+
+func TestConvolveImpossible(t *testing.T) {
+	die := mustNew(t, []float64{1, 1, 1, 1, 1, 1})
+
+	// sums of two dice numbered from 1, so 0 and 1 can't happen
+	c, err := Convolve(die, die, func(i, j uint32) uint32 { return i + j + 2 })
+	if err != nil {
+		t.Fatalf("Couldn't convolve: %v", err)
+	}
+	for i := uint32(0); i < 2; i++ {
+		if p := c.Prob(i); p != 0 {
+			t.Errorf("Impossible sum %v has probability %v", i, p)
+		}
+	}
+}
+
+func TestMergeZeroWeight(t *testing.T) {
+	a := mustNew(t, []float64{1, 3})
+	b := mustNew(t, []float64{1, 1})
+
+	m, err := Merge(a, b, 0, 1, 2)
+	if err != nil {
+		t.Fatalf("Couldn't merge: %v", err)
+	}
+	for i := uint32(0); i < 2; i++ {
+		if p := m.Prob(i); p != 0 {
+			t.Errorf("Index %v of a table merged with weight zero has probability %v", i, p)
+		}
+	}
+}
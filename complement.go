@@ -0,0 +1,62 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// Complements draws two indices from a table at a time, such as for two
+// slots in one request, and never draws both halves of a complementary pair
+// (A/B variants that must not appear together, say).
+//
+// Each of the two draws still follows the table's distribution exactly. The
+// draws are made independently, and if they form a complementary pair the
+// second is replaced by a repeat of the first. Moving the mass of (a,b) to
+// (a,a) and of (b,a) to (b,b) this way leaves the chance of each index in
+// each position unchanged, at the cost of making repeats more likely.
+type Complements struct {
+	al    *Alias
+	pairs map[uint64]struct{}
+}
+
+// NewComplements returns a Complements drawing from al, in which no index of
+// pairs is ever drawn together with its partner, in either order.
+func NewComplements(al *Alias, pairs [][2]uint32) (*Complements, error) {
+	c := &Complements{al: al, pairs: make(map[uint64]struct{}, 2*len(pairs))}
+	for _, p := range pairs {
+		if int(p[0]) >= al.Len() || int(p[1]) >= al.Len() {
+			return nil, errors.New("pair index out of range")
+		}
+		if p[0] == p[1] {
+			return nil, errors.New("an index can't complement itself")
+		}
+		c.pairs[pairKey(p[0], p[1])] = struct{}{}
+		c.pairs[pairKey(p[1], p[0])] = struct{}{}
+	}
+	return c, nil
+}
+
+// Gen2 draws two indices, each with the table's distribution, that aren't a
+// complementary pair. They may be the same index.
+func (c *Complements) Gen2(rng *rand.Rand) (i, j uint32) {
+	i = c.al.Gen(rng)
+	j = c.al.Gen(rng)
+	if c.Complementary(i, j) {
+		j = i
+	}
+	return i, j
+}
+
+// Complementary reports whether i and j are a complementary pair.
+func (c *Complements) Complementary(i, j uint32) bool {
+	_, ok := c.pairs[pairKey(i, j)]
+	return ok
+}
+
+func pairKey(i, j uint32) uint64 {
+	return uint64(i)<<32 | uint64(j)
+}
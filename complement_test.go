@@ -0,0 +1,45 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestComplements(t *testing.T) {
+	dist := []float64{3, 1, 2, 4}
+	a := mustNew(t, dist)
+	c, err := NewComplements(a, [][2]uint32{{0, 3}, {0, 1}})
+	if err != nil {
+		t.Fatalf("Couldn't create Complements: %v", err)
+	}
+
+	gen := func(second bool) func(*rand.Rand) uint32 {
+		return func(rng *rand.Rand) uint32 {
+			i, j := c.Gen2(rng)
+			if c.Complementary(i, j) {
+				t.Fatalf("Gen2 drew complementary pair %v, %v", i, j)
+			}
+			if second {
+				return j
+			}
+			return i
+		}
+	}
+	checkDistribution(t, gen(false), dist, 1)
+	checkDistribution(t, gen(true), dist, 2)
+
+	if !c.Complementary(3, 0) || c.Complementary(1, 3) {
+		t.Errorf("Complementary gave the wrong answers")
+	}
+
+	if _, err := NewComplements(a, [][2]uint32{{2, 2}}); err == nil {
+		t.Errorf("NewComplements with a self pair did not fail")
+	}
+	if _, err := NewComplements(a, [][2]uint32{{0, 4}}); err == nil {
+		t.Errorf("NewComplements with an out of range index did not fail")
+	}
+}
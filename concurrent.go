@@ -0,0 +1,54 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentSampler draws from a table using generators it manages itself,
+// so any number of goroutines can call Gen without sharing, locking or
+// passing around a *rand.Rand.
+//
+// It keeps a pool of generators, each seeded from a SeedSequence with its
+// own index, and a call to Gen borrows one for the draw. Every generator's
+// stream is reproducible from the seed, but which goroutine gets which
+// generator depends on scheduling, so the draws as a whole are not; use
+// GenParallel for reproducible parallel draws.
+type ConcurrentSampler struct {
+	s    Sampler
+	seq  SeedSequence
+	next atomic.Uint64
+	pool sync.Pool
+}
+
+// NewConcurrentSampler returns a ConcurrentSampler drawing from s, with
+// generators seeded from seed.
+func NewConcurrentSampler(s Sampler, seed int64) *ConcurrentSampler {
+	cs := &ConcurrentSampler{s: s, seq: NewSeedSequence(seed)}
+	cs.pool.New = func() any {
+		return cs.seq.Rand(cs.next.Add(1) - 1)
+	}
+	return cs
+}
+
+// Gen draws from the table, as the table's own Gen does.
+func (cs *ConcurrentSampler) Gen() uint32 {
+	rng := cs.pool.Get().(*rand.Rand)
+	i := cs.s.Gen(rng)
+	cs.pool.Put(rng)
+	return i
+}
+
+// GenN fills dst with draws, borrowing a generator once for all of them.
+func (cs *ConcurrentSampler) GenN(dst []uint32) {
+	rng := cs.pool.Get().(*rand.Rand)
+	for i := range dst {
+		dst[i] = cs.s.Gen(rng)
+	}
+	cs.pool.Put(rng)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSampler(t *testing.T) {
+	dist := []float64{4, 1, 3}
+	cs := NewConcurrentSampler(mustNew(t, dist), 1)
+
+	checkDistribution(t, func(*rand.Rand) uint32 {
+		return cs.Gen()
+	}, dist, 1)
+
+	// many goroutines at once; run with -race to check for sharing
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dst := make([]uint32, 100)
+			for i := 0; i < 100; i++ {
+				if v := cs.Gen(); v > 2 {
+					t.Errorf("Gen returned %v", v)
+					return
+				}
+				cs.GenN(dst)
+			}
+		}()
+	}
+	wg.Wait()
+}
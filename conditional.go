@@ -0,0 +1,44 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "errors"
+
+// NewConditional builds, from a joint weight matrix over (Y, X), a table
+// for the distribution of X given each value of Y. joint holds the matrix
+// in row-major order, with one row of cols weights for each y; tables[y]
+// then picks a column in proportion to row y's weights.
+//
+// Weights must be non-negative, and each row must have a positive sum. All
+// the tables share one allocation, built in a single pass over joint. To
+// draw (y, x) pairs from the joint distribution itself, build a table over
+// the row sums, draw y from it, and then draw x from tables[y].
+func NewConditional(joint []float64, cols int) ([]*Alias, error) {
+	if cols < 1 || len(joint)%cols != 0 {
+		return nil, errors.New("joint weights don't fill whole rows")
+	}
+	if int(uint32(cols)) != cols {
+		return nil, errors.New("too many probabilities")
+	}
+
+	rows := len(joint) / cols
+	tables := make([]*Alias, rows)
+	shared := make([]ipiece, len(joint))
+	als := make([]Alias, rows)
+
+	for y := range tables {
+		row := joint[y*cols : (y+1)*cols]
+		total, err := checkProb(row, true)
+		if err != nil {
+			return nil, err
+		}
+
+		als[y].table = shared[y*cols : (y+1)*cols : (y+1)*cols]
+		fill(als[y].table, row, total, vose)
+		tables[y] = &als[y]
+	}
+
+	return tables, nil
+}
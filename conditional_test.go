@@ -0,0 +1,58 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "testing"
+
+func TestNewConditional(t *testing.T) {
+	joint := []float64{
+		1, 3, 0,
+		2, 2, 4,
+	}
+
+	tables, err := NewConditional(joint, 3)
+	if err != nil {
+		t.Fatalf("Couldn't create conditional tables: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Got %v tables, wanted 2", len(tables))
+	}
+
+	checkProbs(t, tables[0], []float64{0.25, 0.75, 0})
+	checkProbs(t, tables[1], []float64{0.25, 0.25, 0.5})
+
+	bad := []struct {
+		joint []float64
+		cols  int
+	}{
+		{[]float64{1, 2, 3}, 2},
+		{[]float64{1, 2}, 0},
+		{[]float64{1, 1, 0, 0}, 2},
+		{[]float64{1, -1}, 2},
+	}
+	for _, b := range bad {
+		if _, err := NewConditional(b.joint, b.cols); err == nil {
+			t.Errorf("NewConditional(%v, %v) did not fail", b.joint, b.cols)
+		}
+	}
+}
+
+func TestNewConditionalZeroFirstColumn(t *testing.T) {
+	// from the second row, moving to column 0 is impossible
+	tables, err := NewConditional([]float64{
+		1, 1,
+		0, 1,
+	}, 2)
+	if err != nil {
+		t.Fatalf("Couldn't create conditional tables: %v", err)
+	}
+
+	if p := tables[1].Prob(0); p != 0 {
+		t.Errorf("Impossible transition has probability %v", p)
+	}
+	if v := tables[1].genFrom(0); v != 1 {
+		t.Errorf("genFrom(0) returned impossible transition %v", v)
+	}
+}
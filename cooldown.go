@@ -0,0 +1,88 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+// Cooldown draws from a table for content rotation: each index it returns
+// is ineligible for the next c draws, and each draw follows the table's
+// distribution renormalized over the eligible indices. This avoids
+// immediate repeats while keeping the choice among the rest proportional to
+// their weights.
+//
+// If c is at least the number of indices with nonzero probability, there
+// are times when every one of them is cooling down. Gen then cuts short
+// the cooldown of the index drawn longest ago, so the effective cooldown
+// is one less than the number of such indices.
+//
+// An index coming off cooldown may not be covered by the private table the
+// underlying Draw rebuilt while it was excluded, in which case the Draw
+// goes back to the full table and may rebuild again in O(n). When a
+// dominant index cycles on and off cooldown, that can cost O(n) per cycle.
+//
+// A Cooldown is safe for concurrent use, provided each goroutine uses its
+// own rng.
+type Cooldown struct {
+	mu     sync.Mutex
+	d      *Draw
+	recent []uint32 // ring of the draws still cooling down
+	head   int      // index in recent of the oldest
+	count  int      // number of draws in recent
+}
+
+// NewCooldown returns a Cooldown drawing from al with a cooldown of c draws.
+// A cooldown longer than the table is the same as one as long as the
+// table, and is shortened to it.
+func NewCooldown(al *Alias, c int) (*Cooldown, error) {
+	if c < 0 {
+		return nil, errors.New("negative cooldown")
+	}
+	if c > al.Len() {
+		c = al.Len()
+	}
+	return &Cooldown{
+		d:      al.BeginDraw(),
+		recent: make([]uint32, c),
+	}, nil
+}
+
+// Gen draws an eligible index and starts its cooldown. It returns false
+// only if the table has no index with nonzero probability.
+func (cd *Cooldown) Gen(rng *rand.Rand) (uint32, bool) {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+
+	i, ok := cd.d.Gen(rng)
+	for !ok && cd.count > 0 {
+		// everything is cooling down; let the oldest go early
+		cd.release()
+		i, ok = cd.d.Gen(rng)
+	}
+	if !ok {
+		return 0, false
+	}
+
+	if len(cd.recent) == 0 {
+		cd.d.Include(i)
+		return i, true
+	}
+	if cd.count == len(cd.recent) {
+		cd.release()
+	}
+	cd.recent[(cd.head+cd.count)%len(cd.recent)] = i
+	cd.count++
+	return i, true
+}
+
+// release ends the cooldown of the oldest recent draw.
+func (cd *Cooldown) release() {
+	cd.d.Include(cd.recent[cd.head])
+	cd.head = (cd.head + 1) % len(cd.recent)
+	cd.count--
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCooldown(t *testing.T) {
+	a := mustNew(t, []float64{5, 3, 1, 1})
+
+	cd, err := NewCooldown(a, 2)
+	if err != nil {
+		t.Fatalf("Couldn't create Cooldown: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	var last [2]uint32
+	for j := 0; j < 10000; j++ {
+		i, ok := cd.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed with items eligible")
+		}
+		if j >= 2 && (i == last[0] || i == last[1]) {
+			t.Fatalf("Gen returned %v after %v", i, last)
+		}
+		last[0], last[1] = last[1], i
+	}
+
+	// after drawing 0 and 1, the rest are renormalized
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		for {
+			cd, _ := NewCooldown(a, 2)
+			i, _ := cd.Gen(rng)
+			j, _ := cd.Gen(rng)
+			if i == 0 && j == 1 {
+				k, _ := cd.Gen(rng)
+				return k
+			}
+		}
+	}, []float64{0, 0, 1, 1}, 1)
+
+	// a zero cooldown is just Gen
+	cd, _ = NewCooldown(a, 0)
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		i, _ := cd.Gen(rng)
+		return i
+	}, []float64{5, 3, 1, 1}, 2)
+
+	// with as many items as the cooldown, the oldest is let go early, so
+	// the draws cycle through all four
+	cd, _ = NewCooldown(a, 4)
+	var seen []uint32
+	for j := 0; j < 20; j++ {
+		i, ok := cd.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed with a cooldown as long as the table")
+		}
+		if j >= 4 && i != seen[j-4] {
+			t.Fatalf("Gen returned %v, wanted the oldest draw %v", i, seen[j-4])
+		}
+		seen = append(seen, i)
+	}
+
+	// a cooldown far longer than the table is cut to the table's length
+	cd, err = NewCooldown(a, 1<<31)
+	if err != nil {
+		t.Fatalf("Couldn't create Cooldown: %v", err)
+	}
+	if len(cd.recent) != a.Len() {
+		t.Errorf("Cooldown of 1<<31 kept room for %v draws, wanted %v", len(cd.recent), a.Len())
+	}
+	seen = seen[:0]
+	for j := 0; j < 20; j++ {
+		i, ok := cd.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed with a cooldown longer than the table")
+		}
+		if j >= 4 && i != seen[j-4] {
+			t.Fatalf("Gen returned %v, wanted the oldest draw %v", i, seen[j-4])
+		}
+		seen = append(seen, i)
+	}
+
+	// zero-weight items don't count toward what can cool down
+	z, err := fromPMF([]float64{0.5, 0, 0.5})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	cd, _ = NewCooldown(z, 5)
+	for j := 0; j < 20; j++ {
+		i, ok := cd.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed with a cooldown longer than the table")
+		}
+		if i == 1 {
+			t.Fatalf("Gen returned zero-weight index 1")
+		}
+	}
+
+	if _, err := NewCooldown(a, -1); err == nil {
+		t.Errorf("NewCooldown with a negative cooldown did not fail")
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// Corpus schedules test cases for a coverage-guided fuzzer: each case has an
+// energy, and Gen picks cases in proportion to their energies. Observe feeds
+// back a score for a run of a case, such as the new coverage it found, and
+// the case's energy becomes a decaying sum of its scores, so cases that
+// have stopped finding anything fade out.
+//
+// Draws come from an alias table over the energies as they were when it
+// was last built. Cases whose energies have changed since, or that were
+// added since, are drawn from a short list instead, and rejected from the
+// table's draws. The table is rebuilt once the list grows past
+// corpusChanged cases, or holds more than half the table's mass, so
+// Observe costs O(n/corpusChanged) amortized and Gen expected O(1) plus the
+// length of the list.
+//
+// A Corpus is safe for concurrent use. MarshalBinary and UnmarshalBinary
+// save and restore it, so a fuzzer's scheduling survives restarts.
+type Corpus struct {
+	mu     sync.Mutex
+	decay  float64
+	energy []float64
+
+	base      *Alias   // table over baseEnergy, or nil if it's all zero
+	baseTotal float64  // sum of baseEnergy
+	changed   []uint32 // cases changed or added since base was built
+	isChanged []bool   // by case, whether it's in changed
+	lost      float64  // sum of baseEnergy over changed cases
+}
+
+// corpusChanged is the number of changed cases that causes a rebuild.
+const corpusChanged = 64
+
+// NewCorpus returns an empty Corpus. decay is the factor each case's energy
+// is multiplied by before a new score is added to it, in [0,1]; a decay of
+// 0 keeps only the latest score, and 1 sums them all.
+func NewCorpus(decay float64) (*Corpus, error) {
+	if !(decay >= 0 && decay <= 1) {
+		return nil, errors.New("decay out of range")
+	}
+	return &Corpus{decay: decay}, nil
+}
+
+func checkEnergy(e float64) error {
+	if e < 0 || math.IsNaN(e) || math.IsInf(e, 0) {
+		return errors.New("an energy is negative or not finite")
+	}
+	return nil
+}
+
+// Add adds a case with the given initial energy, and returns its index.
+func (c *Corpus) Add(energy float64) (uint32, error) {
+	if err := checkEnergy(energy); err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.energy) >= math.MaxUint32 {
+		return 0, errors.New("too many probabilities")
+	}
+
+	i := uint32(len(c.energy))
+	c.energy = append(c.energy, energy)
+	c.isChanged = append(c.isChanged, false)
+	c.touch(i, 0)
+	return i, nil
+}
+
+// Observe records a score for a run of case i: its energy is multiplied by
+// the decay and the score added.
+func (c *Corpus) Observe(i uint32, score float64) error {
+	if err := checkEnergy(score); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if int(i) >= len(c.energy) {
+		return errors.New("case index out of range")
+	}
+
+	old := c.energy[i]
+	c.energy[i] = old*c.decay + score
+	if c.energy[i] != old {
+		c.touch(i, c.baseEnergy(i))
+	}
+	return nil
+}
+
+// Energy returns the current energy of case i.
+func (c *Corpus) Energy(i uint32) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if int(i) >= len(c.energy) {
+		return 0
+	}
+	return c.energy[i]
+}
+
+// Len returns the number of cases.
+func (c *Corpus) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.energy)
+}
+
+// Gen picks a case according to the energies. It returns false if every
+// case has zero energy.
+func (c *Corpus) Gen(rng *rand.Rand) (uint32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	head := float64(0)
+	for _, i := range c.changed {
+		head += c.energy[i]
+	}
+	tail := c.baseTotal - c.lost
+	if tail < 0 || c.base == nil {
+		tail = 0
+	}
+	if head+tail <= 0 {
+		return 0, false
+	}
+
+	x := rng.Float64() * (head + tail)
+	if x < head {
+		for _, i := range c.changed {
+			if x < c.energy[i] {
+				return i, true
+			}
+			x -= c.energy[i]
+		}
+		// rounding error walked us off the end of the list; let the table
+		// take it, unless it's empty
+		if tail <= 0 {
+			return c.changed[len(c.changed)-1], true
+		}
+	}
+
+	for {
+		i := c.base.Gen(rng)
+		if !c.isChanged[i] {
+			return i, true
+		}
+	}
+}
+
+// baseEnergy returns the energy case i had when the table was built.
+func (c *Corpus) baseEnergy(i uint32) float64 {
+	if c.base == nil || int(i) >= len(c.base.table) {
+		return 0
+	}
+	return c.base.Prob(i) * c.baseTotal
+}
+
+// touch marks case i as changed, where its energy in the table was
+// baseEnergy, and rebuilds the table if too much has changed.
+func (c *Corpus) touch(i uint32, baseEnergy float64) {
+	if !c.isChanged[i] {
+		c.isChanged[i] = true
+		c.changed = append(c.changed, i)
+		c.lost += baseEnergy
+	}
+
+	if len(c.changed) > corpusChanged || c.lost > c.baseTotal/2 {
+		c.rebuild()
+	}
+}
+
+func (c *Corpus) rebuild() {
+	for _, i := range c.changed {
+		c.isChanged[i] = false
+	}
+	c.changed = c.changed[:0]
+	c.lost = 0
+
+	c.base = nil
+	c.baseTotal = sum(c.energy)
+	if c.baseTotal > 0 {
+		// energies were checked as they came in, so this can't fail
+		base, err := fromPMF(c.energy)
+		if err != nil {
+			panic(err)
+		}
+		c.base = base
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaller.
+//
+// The format is the decay as a little endian IEEE 754 double, the number of
+// cases as a little endian uint32, and then their energies as little endian
+// IEEE 754 doubles.
+func (c *Corpus) MarshalBinary() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]byte, 0, 12+8*len(c.energy))
+	out = binary.LittleEndian.AppendUint64(out, math.Float64bits(c.decay))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(c.energy)))
+	for _, e := range c.energy {
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(e))
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaller.
+func (c *Corpus) UnmarshalBinary(p []byte) error {
+	if len(p) < 12 {
+		return errors.New("bad data length")
+	}
+	decay := math.Float64frombits(binary.LittleEndian.Uint64(p))
+	n := binary.LittleEndian.Uint32(p[8:])
+	p = p[12:]
+
+	if !(decay >= 0 && decay <= 1) {
+		return errors.New("bad data: decay out of range")
+	}
+	if uint64(n)*8 != uint64(len(p)) {
+		return errors.New("bad data length")
+	}
+
+	energy := make([]float64, n)
+	for i := range energy {
+		energy[i] = math.Float64frombits(binary.LittleEndian.Uint64(p))
+		p = p[8:]
+		if err := checkEnergy(energy[i]); err != nil {
+			return errors.New("bad data: " + err.Error())
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.decay = decay
+	c.energy = energy
+	c.isChanged = make([]bool, n)
+	c.changed = nil
+	c.rebuild()
+	return nil
+}
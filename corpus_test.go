@@ -0,0 +1,99 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCorpus(t *testing.T) {
+	c, err := NewCorpus(0.5)
+	if err != nil {
+		t.Fatalf("Couldn't create Corpus: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	if i, ok := c.Gen(rng); ok {
+		t.Errorf("Gen returned %v from an empty Corpus", i)
+	}
+
+	// enough cases that some go into the table, and some stay in the list
+	want := make([]float64, 100)
+	for i := range want {
+		want[i] = float64(i%5 + 1)
+		if _, err := c.Add(want[i]); err != nil {
+			t.Fatalf("Couldn't Add: %v", err)
+		}
+	}
+
+	gen := func(rng *rand.Rand) uint32 {
+		i, ok := c.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed with energy left")
+		}
+		return i
+	}
+	checkDistribution(t, gen, want, 1)
+
+	// 3*0.5 + 7 = 8.5
+	if err := c.Observe(2, 7); err != nil {
+		t.Fatalf("Couldn't Observe: %v", err)
+	}
+	want[2] = 8.5
+	if c.Energy(2) != 8.5 {
+		t.Errorf("Energy(2) was %v, wanted 8.5", c.Energy(2))
+	}
+	for i := 0; i < 10; i++ {
+		c.Observe(3, 0)
+	}
+	want[3] = 4.0 / 1024
+	checkDistribution(t, gen, want, 2)
+
+	data, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Couldn't MarshalBinary: %v", err)
+	}
+	c2 := &Corpus{}
+	if err := c2.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Couldn't UnmarshalBinary: %v", err)
+	}
+	if !reflect.DeepEqual(c2.energy, c.energy) || c2.decay != c.decay {
+		t.Errorf("Unmarshalled Corpus differs from original")
+	}
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		i, _ := c2.Gen(rng)
+		return i
+	}, want, 3)
+
+	for i := 0; i < len(data); i++ {
+		if err := c2.UnmarshalBinary(data[:i]); err == nil {
+			t.Errorf("UnmarshalBinary of %v truncated bytes did not fail", len(data)-i)
+		}
+	}
+
+	if err := c.Observe(100, 1); err == nil {
+		t.Errorf("Observe out of range did not fail")
+	}
+	if _, err := c.Add(-1); err == nil {
+		t.Errorf("Add with negative energy did not fail")
+	}
+	if _, err := NewCorpus(2); err == nil {
+		t.Errorf("NewCorpus with decay out of range did not fail")
+	}
+}
+
+func TestCorpusAllZero(t *testing.T) {
+	c, _ := NewCorpus(0)
+	c.Add(1)
+	c.Add(1)
+	c.Observe(0, 0)
+	c.Observe(1, 0)
+
+	if i, ok := c.Gen(rand.New(rand.NewSource(1))); ok {
+		t.Errorf("Gen returned %v with no energy", i)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// CountingSource wraps a rand.Source and counts the random words drawn from
+// it, so the entropy consumed by a sampler can be measured exactly.
+//
+// Use it as
+//
+//	cs := alias.NewCountingSource(rand.NewSource(1))
+//	rng := rand.New(cs)
+//	before := cs.Count()
+//	a.Gen(rng)
+//	used := cs.Count() - before
+//
+// Every call to Int63 or Uint64 on the underlying source counts as one word.
+// If the underlying source doesn't implement rand.Source64, Uint64 is built
+// from two Int63 calls and counts as two words.
+type CountingSource struct {
+	src   rand.Source
+	src64 rand.Source64
+	n     uint64
+}
+
+// NewCountingSource returns a CountingSource drawing from src.
+func NewCountingSource(src rand.Source) *CountingSource {
+	cs := &CountingSource{src: src}
+	cs.src64, _ = src.(rand.Source64)
+	return cs
+}
+
+// Count returns the number of words drawn so far.
+func (cs *CountingSource) Count() uint64 {
+	return atomic.LoadUint64(&cs.n)
+}
+
+// Reset sets the count back to zero.
+func (cs *CountingSource) Reset() {
+	atomic.StoreUint64(&cs.n, 0)
+}
+
+// Int63 implements rand.Source.
+func (cs *CountingSource) Int63() int64 {
+	atomic.AddUint64(&cs.n, 1)
+	return cs.src.Int63()
+}
+
+// Uint64 implements rand.Source64.
+func (cs *CountingSource) Uint64() uint64 {
+	if cs.src64 != nil {
+		atomic.AddUint64(&cs.n, 1)
+		return cs.src64.Uint64()
+	}
+	return uint64(cs.Int63())>>31 | uint64(cs.Int63())<<32
+}
+
+// Seed implements rand.Source. It does not reset the count.
+func (cs *CountingSource) Seed(seed int64) {
+	cs.src.Seed(seed)
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCountingSource(t *testing.T) {
+	a, err := New([]float64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	cs := NewCountingSource(rand.NewSource(1))
+	rng := rand.New(cs)
+
+	for i := 0; i < 100; i++ {
+		a.Gen(rng)
+	}
+	if cs.Count() != 100 {
+		t.Errorf("Gen used %v words over 100 draws, wanted 100", cs.Count())
+	}
+
+	cs.Reset()
+	if cs.Count() != 0 {
+		t.Errorf("Count was %v after Reset", cs.Count())
+	}
+}
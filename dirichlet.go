@@ -0,0 +1,88 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Perturb returns a table for the mixture (1-epsilon)*p + epsilon*eta, where
+// p is the table's encoded distribution and eta is fresh noise drawn from a
+// symmetric Dirichlet distribution with concentration alpha over the
+// indices p can return. This is the exploration noise used in self-play
+// training: call Perturb once per epoch or episode and draw from the
+// result.
+//
+// Small alpha concentrates the noise on a few indices; large alpha spreads
+// it evenly. epsilon must be in [0,1] and alpha positive. The new table is
+// built from the encoded probabilities, in O(n).
+func (al *Alias) Perturb(rng *rand.Rand, alpha, epsilon float64) (*Alias, error) {
+	if !(alpha > 0) || math.IsInf(alpha, 0) {
+		return nil, errors.New("concentration out of range")
+	}
+	if !(epsilon >= 0 && epsilon <= 1) {
+		return nil, errors.New("noise fraction out of range")
+	}
+
+	p := al.pmf()
+
+	// draw the gammas in log space, since for small alpha they can be far
+	// too small to represent, and normalize from the largest
+	logs := make([]float64, len(p))
+	max := math.Inf(-1)
+	for i, pi := range p {
+		logs[i] = math.Inf(-1)
+		if pi > 0 {
+			logs[i] = logGammaVariate(rng, alpha)
+			max = math.Max(max, logs[i])
+		}
+	}
+
+	total := float64(0)
+	for i, l := range logs {
+		logs[i] = math.Exp(l - max)
+		total += logs[i]
+	}
+
+	pmf := make([]float64, len(p))
+	for i, pi := range p {
+		pmf[i] = (1-epsilon)*pi + epsilon*logs[i]/total
+	}
+	return fromPMF(pmf)
+}
+
+// gammaVariate returns a draw from the Gamma distribution with the given
+// shape and unit scale.
+func gammaVariate(rng *rand.Rand, shape float64) float64 {
+	return math.Exp(logGammaVariate(rng, shape))
+}
+
+// logGammaVariate returns the logarithm of a draw from the Gamma
+// distribution with the given shape and unit scale, by the method of
+// Marsaglia and Tsang.
+func logGammaVariate(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// Gamma(a) is distributed as Gamma(a+1) * U^(1/a)
+		return logGammaVariate(rng, shape+1) + math.Log(1-rng.Float64())/shape
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+
+		u := 1 - rng.Float64()
+		if math.Log(u) < x*x/2+d-d*v+d*math.Log(v) {
+			return math.Log(d * v)
+		}
+	}
+}
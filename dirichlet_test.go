@@ -0,0 +1,81 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPerturb(t *testing.T) {
+	a := mustNew(t, []float64{6, 2, 2})
+	rng := rand.New(rand.NewSource(1))
+
+	// no noise
+	b, err := a.Perturb(rng, 0.3, 0)
+	if err != nil {
+		t.Fatalf("Couldn't Perturb: %v", err)
+	}
+	checkProbs(t, b, []float64{0.6, 0.2, 0.2})
+
+	// the noise averages out to uniform
+	const trials = 10000
+	mean := make([]float64, 3)
+	for i := 0; i < trials; i++ {
+		b, err := a.Perturb(rng, 0.3, 0.5)
+		if err != nil {
+			t.Fatalf("Couldn't Perturb: %v", err)
+		}
+		for j := range mean {
+			mean[j] += b.Prob(uint32(j)) / trials
+		}
+	}
+	for j, want := range []float64{0.3 + 0.5/3, 0.1 + 0.5/3, 0.1 + 0.5/3} {
+		if math.Abs(mean[j]-want) > 0.01 {
+			t.Errorf("Mean Prob(%v) was %v, wanted %v", j, mean[j], want)
+		}
+	}
+
+	// indices with zero probability get no noise
+	z, err := fromPMF([]float64{1, 1, 0})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	b, err = z.Perturb(rng, 0.001, 1)
+	if err != nil {
+		t.Fatalf("Couldn't Perturb: %v", err)
+	}
+	if p := b.Prob(2); p > 1e-9 {
+		t.Errorf("Prob(2) was %v after perturbing", p)
+	}
+
+	if _, err := a.Perturb(rng, 0, 0.5); err == nil {
+		t.Errorf("Perturb with zero concentration did not fail")
+	}
+	if _, err := a.Perturb(rng, 1, 2); err == nil {
+		t.Errorf("Perturb with noise fraction out of range did not fail")
+	}
+}
+
+func TestGammaVariate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const trials = 100000
+
+	for _, shape := range []float64{0.2, 1, 7.5} {
+		mean, sq := float64(0), float64(0)
+		for i := 0; i < trials; i++ {
+			x := gammaVariate(rng, shape)
+			mean += x / trials
+			sq += x * x / trials
+		}
+		variance := sq - mean*mean
+
+		// the mean and variance are both the shape
+		if math.Abs(mean-shape) > 0.05*shape || math.Abs(variance-shape) > 0.1*shape {
+			t.Errorf("Gamma(%v) had mean %v and variance %v", shape, mean, variance)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+)
+
+// Draw is a session of draws from a table without replacement: every index
+// it returns is excluded from its later draws, with the remaining indices
+// renormalized. It leaves the table itself untouched, so many sessions can
+// share one table, and a session is cheap to start and to throw away.
+//
+// Draws are made from the table by rejecting excluded results. Once less
+// than half of the table's mass remains, rejection would get slow, so the
+// session builds a private table over just the remaining indices. Each
+// rebuild at least halves the mass left to cover, so the expected number of
+// tries per draw stays below two.
+//
+// A Draw is not safe for concurrent use.
+type Draw struct {
+	al       *Alias
+	pmf      []float64
+	excluded map[uint32]bool
+	mass     float64 // remaining probability mass
+	left     int     // remaining indices with nonzero probability
+
+	cur     *Alias   // table currently drawn from; al or a rebuilt one
+	curIdx  []uint32 // indices of cur's items in al, or nil if cur is al
+	curMass float64  // mass of al covered by cur
+}
+
+// BeginDraw starts a session of draws without replacement.
+func (al *Alias) BeginDraw() *Draw {
+	pmf := al.pmf()
+	return &Draw{
+		al:      al,
+		pmf:     pmf,
+		mass:    1,
+		left:    al.pmfLive,
+		cur:     al,
+		curMass: 1,
+	}
+}
+
+// Mass returns the total probability of the indices not yet excluded.
+func (d *Draw) Mass() float64 {
+	return d.mass
+}
+
+// Exclude removes i from the indices that later draws may return.
+func (d *Draw) Exclude(i uint32) {
+	if int(i) >= len(d.pmf) || d.excluded[i] {
+		return
+	}
+	if d.excluded == nil {
+		d.excluded = make(map[uint32]bool)
+	}
+	d.excluded[i] = true
+
+	if d.pmf[i] > 0 {
+		d.left--
+		d.mass -= d.pmf[i]
+	}
+	if d.left == 0 || d.mass < 0 {
+		d.mass = 0
+	}
+}
+
+// Include reverses Exclude, letting later draws return i again.
+//
+// If the session has rebuilt a private table that doesn't cover i, it goes
+// back to drawing from the full table, and the next draw may rebuild again
+// in O(n). Sessions that repeatedly exclude and include a heavy index pay
+// that cost each time.
+func (d *Draw) Include(i uint32) {
+	if !d.excluded[i] {
+		return
+	}
+	delete(d.excluded, i)
+
+	if d.pmf[i] > 0 {
+		d.left++
+		d.mass += d.pmf[i]
+	}
+
+	// a rebuilt table only covers what remained when it was built; go back
+	// to the full table if i isn't among them
+	if d.curIdx != nil {
+		k := sort.Search(len(d.curIdx), func(k int) bool { return d.curIdx[k] >= i })
+		if k == len(d.curIdx) || d.curIdx[k] != i {
+			d.cur = d.al
+			d.curIdx = nil
+			d.curMass = 1
+		}
+	}
+}
+
+// Gen draws an index not yet excluded, according to the distribution
+// renormalized over those indices, and excludes it. It returns false if no
+// indices remain.
+func (d *Draw) Gen(rng *rand.Rand) (uint32, bool) {
+	i, ok := d.peek(rng)
+	if ok {
+		d.Exclude(i)
+	}
+	return i, ok
+}
+
+// GenContext is Gen, but gives up with ctx.Err() if ctx is done before it
+// starts. A single draw can't be interrupted, but takes expected O(1) time
+// apart from an occasional O(n) rebuild, so checking between draws bounds
+// how far a session of draws can overrun a deadline.
+func (d *Draw) GenContext(ctx context.Context, rng *rand.Rand) (uint32, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+	i, ok := d.Gen(rng)
+	return i, ok, nil
+}
+
+// peek is Gen without excluding the result.
+func (d *Draw) peek(rng *rand.Rand) (uint32, bool) {
+	if d.left == 0 {
+		return 0, false
+	}
+
+	if d.mass < d.curMass/2 {
+		d.rebuild()
+	}
+
+	for {
+		i := d.cur.Gen(rng)
+		if d.curIdx != nil {
+			i = d.curIdx[i]
+		}
+		if !d.excluded[i] {
+			return i, true
+		}
+	}
+}
+
+func (d *Draw) rebuild() {
+	var idx []uint32
+	var prob []float64
+	mass := float64(0)
+	for i, p := range d.pmf {
+		if p > 0 && !d.excluded[uint32(i)] {
+			idx = append(idx, uint32(i))
+			prob = append(prob, p)
+			mass += p
+		}
+	}
+
+	// prob is non-empty and positive, so this can't fail
+	cur, err := New(prob)
+	if err != nil {
+		panic(err)
+	}
+
+	d.cur = cur
+	d.curIdx = idx
+	d.curMass = mass
+
+	// resync with the exact sum, so rounding can't build up
+	d.mass = mass
+}
+
+// GenDistinct draws up to k distinct indices, each according to the
+// distribution renormalized over the indices not yet drawn. It stops early
+// when the probability mass of the undrawn indices falls below minMass, and
+// returns the draws along with that remaining mass.
+//
+// This suits picking a few distinct items to try in turn, such as mirrors,
+// where items beyond a point are too unlikely to be worth trying.
+func (al *Alias) GenDistinct(rng *rand.Rand, k int, minMass float64) ([]uint32, float64) {
+	d := al.BeginDraw()
+
+	var out []uint32
+	for len(out) < k && d.mass >= minMass {
+		i, ok := d.Gen(rng)
+		if !ok {
+			break
+		}
+		out = append(out, i)
+	}
+
+	return out, d.mass
+}
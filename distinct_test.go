@@ -0,0 +1,120 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestGenDistinct(t *testing.T) {
+	a, err := New([]float64{5, 3, 1, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		out, mass := a.GenDistinct(rng, 10, 0)
+		if len(out) != 4 || mass != 0 {
+			t.Fatalf("GenDistinct returned %v, %v; wanted all four items", out, mass)
+		}
+		seen := make(map[uint32]bool)
+		for _, v := range out {
+			if seen[v] {
+				t.Fatalf("GenDistinct returned a repeat: %v", out)
+			}
+			seen[v] = true
+		}
+	}
+
+	// the second draw follows the renormalized distribution
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		for {
+			out, _ := a.GenDistinct(rng, 2, 0)
+			if out[0] == 0 {
+				return out[1]
+			}
+		}
+	}, []float64{0, 3, 1, 1}, 2)
+
+	// stops as soon as less than minMass remains
+	pmf := []float64{0.5, 0.3, 0.1, 0.1}
+	for i := 0; i < 1000; i++ {
+		out, mass := a.GenDistinct(rng, 10, 0.25)
+		last := out[len(out)-1]
+		if mass >= 0.25 || mass+pmf[last] < 0.25 {
+			t.Fatalf("GenDistinct(_, 10, 0.25) returned %v with %v mass left", out, mass)
+		}
+	}
+}
+
+func TestBeginDraw(t *testing.T) {
+	a, err := New([]float64{5, 3, 1, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	// excluding up front renormalizes over the rest
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		d := a.BeginDraw()
+		d.Exclude(0)
+		d.Exclude(3)
+		i, ok := d.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed with items remaining")
+		}
+		return i
+	}, []float64{0, 3, 1, 0}, 1)
+
+	d := a.BeginDraw()
+	rng := rand.New(rand.NewSource(2))
+	for j := 0; j < 4; j++ {
+		if _, ok := d.Gen(rng); !ok {
+			t.Fatalf("Gen failed with items remaining")
+		}
+	}
+	if i, ok := d.Gen(rng); ok {
+		t.Errorf("Gen returned %v with nothing remaining", i)
+	}
+	if d.Mass() != 0 {
+		t.Errorf("Mass was %v with nothing remaining", d.Mass())
+	}
+}
+
+func TestDrawInclude(t *testing.T) {
+	a := mustNew(t, []float64{5, 3, 1, 1})
+
+	// including after a rebuild goes back to drawing from everything left
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		d := a.BeginDraw()
+		d.Exclude(0)
+		d.Exclude(1)
+		d.peek(rng) // rebuilds over 2 and 3
+		d.Include(0)
+		i, _ := d.Gen(rng)
+		return i
+	}, []float64{5, 0, 1, 1}, 1)
+}
+
+func TestDrawGenContext(t *testing.T) {
+	a := mustNew(t, []float64{1, 2})
+	rng := rand.New(rand.NewSource(1))
+
+	d := a.BeginDraw()
+	if _, ok, err := d.GenContext(context.Background(), rng); !ok || err != nil {
+		t.Errorf("GenContext failed: %v, %v", ok, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := d.GenContext(ctx, rng); err != context.Canceled {
+		t.Errorf("GenContext after cancel returned %v", err)
+	}
+	if d.Mass() == 0 {
+		t.Errorf("GenContext after cancel drew anyway")
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteDot writes the table's slot structure as a Graphviz DOT graph, for
+// debugging. Each index is a node labeled with its total probability, and
+// each slot that can take its alias has an edge to the alias, labeled with
+// the probability that flows along it. The probability a slot keeps for its
+// own index is shown in the node as "keeps".
+func (al *Alias) WriteDot(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	pmf := al.pmf()
+
+	fmt.Fprintln(bw, "digraph alias {")
+	for i, piece := range al.table {
+		direct, total := slotCounts(al.table, uint32(i))
+		fmt.Fprintf(bw, "\t%d [label=\"%d\\np=%.6g\\nkeeps %.6g\"];\n",
+			i, i, pmf[i], float64(direct)/(1<<31))
+		if total > direct {
+			fmt.Fprintf(bw, "\t%d -> %d [label=\"%.6g\"];\n",
+				i, piece.alias, float64(total-direct)/(1<<31))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	a := mustNew(t, []float64{1, 3})
+
+	var buf bytes.Buffer
+	if err := a.WriteDot(&buf); err != nil {
+		t.Fatalf("Couldn't WriteDot: %v", err)
+	}
+
+	want := `digraph alias {
+	0 [label="0\np=0.25\nkeeps 0.25"];
+	0 -> 1 [label="0.25"];
+	1 [label="1\np=0.75\nkeeps 0.5"];
+}
+`
+	if buf.String() != want {
+		t.Errorf("WriteDot wrote\n%s\nwanted\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Dynamic is a sampler whose weights can be changed one at a time in O(1),
+// for large tables whose weights change too often to rebuild.
+//
+// Indices are grouped into buckets by the power of two just above their
+// weight. A draw picks a bucket in proportion to that power of two times the
+// bucket's size, then a uniformly random index in the bucket, and accepts it
+// with probability its weight over the bucket's power of two, which is at
+// least a half, trying again otherwise. Every index ends up drawn exactly in
+// proportion to its weight, in fewer than two tries on average, and the
+// bucket sizes are integers, so no floating point error builds up however
+// many updates are made. A draw costs O(buckets), and there are at most a
+// few dozen buckets unless the weights span an enormous range.
+//
+// A Dynamic is safe for concurrent use.
+type Dynamic struct {
+	mu      sync.RWMutex
+	weights []float64
+	pos     []int            // position of each index in its bucket
+	buckets map[int][]uint32 // indices, by the exponent of their bucket
+	exps    []int            // exponents of the nonempty buckets, ascending
+}
+
+// NewDynamic returns a Dynamic with the given initial weights. Weights must
+// be non-negative and finite; an index with weight zero is never drawn.
+func NewDynamic(weights []float64) (*Dynamic, error) {
+	if int(uint32(len(weights))) != len(weights) {
+		return nil, errors.New("too many probabilities")
+	}
+
+	d := &Dynamic{
+		weights: make([]float64, len(weights)),
+		pos:     make([]int, len(weights)),
+		buckets: make(map[int][]uint32),
+	}
+	for i, w := range weights {
+		if err := checkDynamicWeight(w); err != nil {
+			return nil, err
+		}
+		d.weights[i] = w
+		d.insert(uint32(i))
+	}
+	return d, nil
+}
+
+func checkDynamicWeight(w float64) error {
+	if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+		return errors.New("a weight is negative or not finite")
+	}
+	return nil
+}
+
+// bucketExp returns the exponent of the bucket for weight w, the e for
+// which 2^(e-1) <= w < 2^e.
+func bucketExp(w float64) int {
+	_, e := math.Frexp(w)
+	return e
+}
+
+// insert adds i to the bucket for its weight, if it's nonzero.
+func (d *Dynamic) insert(i uint32) {
+	if d.weights[i] == 0 {
+		return
+	}
+
+	e := bucketExp(d.weights[i])
+	b, ok := d.buckets[e]
+	if !ok {
+		j := sort.SearchInts(d.exps, e)
+		d.exps = append(d.exps, 0)
+		copy(d.exps[j+1:], d.exps[j:])
+		d.exps[j] = e
+	}
+	d.pos[i] = len(b)
+	d.buckets[e] = append(b, i)
+}
+
+// remove takes i out of its bucket, if it's in one.
+func (d *Dynamic) remove(i uint32) {
+	if d.weights[i] == 0 {
+		return
+	}
+
+	e := bucketExp(d.weights[i])
+	b := d.buckets[e]
+	last := b[len(b)-1]
+	b[d.pos[i]] = last
+	d.pos[last] = d.pos[i]
+	b = b[:len(b)-1]
+
+	if len(b) > 0 {
+		d.buckets[e] = b
+		return
+	}
+	delete(d.buckets, e)
+	j := sort.SearchInts(d.exps, e)
+	d.exps = append(d.exps[:j], d.exps[j+1:]...)
+}
+
+// Len returns the number of indices.
+func (d *Dynamic) Len() int {
+	return len(d.weights)
+}
+
+// Weight returns the current weight of index i, or zero if i is out of
+// range.
+func (d *Dynamic) Weight(i uint32) float64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if int(i) >= len(d.weights) {
+		return 0
+	}
+	return d.weights[i]
+}
+
+// UpdateWeight changes the weight of index i, in O(1) time unless it empties
+// or starts a bucket.
+func (d *Dynamic) UpdateWeight(i uint32, w float64) error {
+	if err := checkDynamicWeight(w); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if int(i) >= len(d.weights) {
+		return errors.New("index out of range")
+	}
+
+	if d.weights[i] != 0 && w != 0 && bucketExp(d.weights[i]) == bucketExp(w) {
+		d.weights[i] = w
+		return nil
+	}
+
+	d.remove(i)
+	d.weights[i] = w
+	d.insert(i)
+	return nil
+}
+
+// Gen draws an index with probability proportional to its current weight.
+// It returns false if every weight is zero.
+func (d *Dynamic) Gen(rng *rand.Rand) (uint32, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if len(d.exps) == 0 {
+		return 0, false
+	}
+
+	// bucket bounds relative to the largest, so they can't overflow
+	top := d.exps[len(d.exps)-1]
+	total := float64(0)
+	for _, e := range d.exps {
+		total += math.Ldexp(float64(len(d.buckets[e])), e-top)
+	}
+
+	for {
+		x := rng.Float64() * total
+		e := top
+		for _, e = range d.exps {
+			x -= math.Ldexp(float64(len(d.buckets[e])), e-top)
+			if x < 0 {
+				break
+			}
+		}
+
+		b := d.buckets[e]
+		i := b[rng.Intn(len(b))]
+		if rng.Float64() < math.Ldexp(d.weights[i], -e) {
+			return i, true
+		}
+	}
+}
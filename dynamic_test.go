@@ -0,0 +1,62 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDynamic(t *testing.T) {
+	dist := []float64{1, 5, 0, 3e10, 0.25, 7}
+	d, err := NewDynamic(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create Dynamic: %v", err)
+	}
+
+	gen := func(rng *rand.Rand) uint32 {
+		i, ok := d.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed with nonzero weights")
+		}
+		return i
+	}
+
+	dist[3] = 3
+	if err := d.UpdateWeight(3, 3); err != nil {
+		t.Fatalf("Couldn't update weight: %v", err)
+	}
+	checkDistribution(t, gen, dist, 1)
+
+	// moving within a bucket, between buckets, to and from zero
+	for i, w := range []float64{6, 0, 2, 0.5, 1e-3, 0} {
+		dist[i] = w
+		if err := d.UpdateWeight(uint32(i), w); err != nil {
+			t.Fatalf("Couldn't update weight: %v", err)
+		}
+	}
+	checkDistribution(t, gen, dist, 2)
+
+	if d.Weight(2) != 2 || d.Weight(9) != 0 {
+		t.Errorf("Weight returned the wrong weights")
+	}
+
+	for i := range dist {
+		d.UpdateWeight(uint32(i), 0)
+	}
+	if i, ok := d.Gen(rand.New(rand.NewSource(1))); ok {
+		t.Errorf("Gen returned %v with all weights zero", i)
+	}
+
+	if err := d.UpdateWeight(6, 1); err == nil {
+		t.Errorf("UpdateWeight out of range did not fail")
+	}
+	if err := d.UpdateWeight(0, -1); err == nil {
+		t.Errorf("UpdateWeight to a negative weight did not fail")
+	}
+	if _, err := NewDynamic([]float64{1, -1}); err == nil {
+		t.Errorf("NewDynamic with a negative weight did not fail")
+	}
+}
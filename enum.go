@@ -0,0 +1,83 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+// Integer is the set of types an Enum can pick, covering enums declared as
+// named integer types.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Enum picks values of an enumerated type, so callers get a typed value back
+// rather than an index to convert by hand. For example,
+//
+//	type TrafficClass int
+//
+//	const (
+//		Bulk TrafficClass = iota
+//		Interactive
+//		Realtime
+//	)
+//
+//	e, err := alias.NewEnum(map[TrafficClass]float64{
+//		Bulk:        70,
+//		Interactive: 25,
+//		Realtime:    5,
+//	})
+//
+// Gen on e then returns a TrafficClass.
+type Enum[E Integer] struct {
+	al     *Alias
+	values []E
+}
+
+// Create a new Enum picking each value in weights with probability
+// proportional to its weight. Weights must be positive, as with New. The
+// table's indices follow the values in increasing order, so the table
+// doesn't depend on map iteration order.
+func NewEnum[E Integer](weights map[E]float64) (*Enum[E], error) {
+	if len(weights) == 0 {
+		return nil, errors.New("too few probabilities")
+	}
+
+	values := make([]E, 0, len(weights))
+	for v := range weights {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(a, b int) bool { return values[a] < values[b] })
+
+	prob := make([]float64, len(values))
+	for i, v := range values {
+		prob[i] = weights[v]
+	}
+
+	al, err := New(prob)
+	if err != nil {
+		return nil, err
+	}
+	return &Enum[E]{al: al, values: values}, nil
+}
+
+// Gen picks a value according to the weights.
+func (e *Enum[E]) Gen(rng *rand.Rand) E {
+	return e.values[e.al.Gen(rng)]
+}
+
+// Prob returns the probability that Gen returns v, which is zero if v was
+// not given a weight.
+func (e *Enum[E]) Prob(v E) float64 {
+	i := sort.Search(len(e.values), func(i int) bool { return e.values[i] >= v })
+	if i == len(e.values) || e.values[i] != v {
+		return 0
+	}
+	return e.al.Prob(uint32(i))
+}
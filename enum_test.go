@@ -0,0 +1,48 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type trafficClass int8
+
+const (
+	bulk trafficClass = iota - 1
+	interactive
+	realtime
+)
+
+func TestEnum(t *testing.T) {
+	e, err := NewEnum(map[trafficClass]float64{
+		bulk:        7,
+		interactive: 2,
+		realtime:    1,
+	})
+	if err != nil {
+		t.Fatalf("Couldn't create Enum: %v", err)
+	}
+
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		return uint32(e.Gen(rng) - bulk)
+	}, []float64{7, 2, 1}, 1)
+
+	if p := e.Prob(interactive); math.Abs(p-0.2) > 1e-8 {
+		t.Errorf("Prob(interactive) was %v, wanted 0.2", p)
+	}
+	if p := e.Prob(5); p != 0 {
+		t.Errorf("Prob of an unweighted value was %v", p)
+	}
+
+	if _, err := NewEnum(map[trafficClass]float64{}); err == nil {
+		t.Errorf("NewEnum with no values did not fail")
+	}
+	if _, err := NewEnum(map[trafficClass]float64{bulk: 0}); err == nil {
+		t.Errorf("NewEnum with a zero weight did not fail")
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "math/rand"
+
+// Explanation records how Gen reached a result, for audit logs of weighted
+// decisions. Together with the table, it's enough to check the result by
+// hand: Slot is Random modulo the table's length, and the result is Slot if
+// 0 < Threshold and Random <= Threshold, and Alias otherwise.
+type Explanation struct {
+	Random    uint32 // the uniform value drawn, in [0,2^31)
+	Slot      uint32 // the slot it picked
+	Threshold uint32 // the slot's threshold
+	Alias     uint32 // the slot's alias target
+	TookAlias bool   // whether the result was the alias target
+}
+
+// GenExplain is Gen, but also explains how the result was reached. It draws
+// the same result as Gen from the same rng state.
+func (al *Alias) GenExplain(rng *rand.Rand) (uint32, Explanation) {
+	return al.genExplainFrom(uint32(rng.Int31()))
+}
+
+// genExplainFrom is genFrom, explained.
+func (al *Alias) genExplainFrom(ri uint32) (uint32, Explanation) {
+	w := ri % uint32(len(al.table))
+	piece := al.table[w]
+
+	e := Explanation{
+		Random:    ri,
+		Slot:      w,
+		Threshold: piece.prob,
+		Alias:     piece.alias,
+		TookAlias: ri > piece.prob || piece.prob == 0,
+	}
+	if e.TookAlias {
+		return piece.alias, e
+	}
+	return w, e
+}
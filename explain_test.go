@@ -0,0 +1,59 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGenExplain(t *testing.T) {
+	a := mustNew(t, []float64{10, 1, 2, 3})
+
+	rng1 := rand.New(rand.NewSource(1))
+	rng2 := rand.New(rand.NewSource(1))
+	tookAlias := 0
+	for i := 0; i < 1000; i++ {
+		want := a.Gen(rng1)
+		got, e := a.GenExplain(rng2)
+		if got != want {
+			t.Fatalf("GenExplain returned %v, Gen %v", got, want)
+		}
+
+		// the explanation is enough to reach the result
+		if e.Slot != e.Random%4 {
+			t.Fatalf("Explanation %+v has the wrong slot", e)
+		}
+		keep := 0 < e.Threshold && e.Random <= e.Threshold
+		check := e.Slot
+		if !keep {
+			check = e.Alias
+		}
+		if check != got || e.TookAlias == keep {
+			t.Fatalf("Explanation %+v doesn't lead to %v", e, got)
+		}
+		if e.TookAlias {
+			tookAlias++
+		}
+	}
+
+	if tookAlias == 0 {
+		t.Errorf("No draw took an alias")
+	}
+
+	// a zero threshold keeps nothing, not even a Random of zero
+	z, err := fromPMF([]float64{0, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	got, e := z.genExplainFrom(0)
+	want := Explanation{Random: 0, Slot: 0, Threshold: 0, Alias: 1, TookAlias: true}
+	if got != 1 || e != want {
+		t.Errorf("genExplainFrom(0) returned %v, %+v; wanted 1, %+v", got, e, want)
+	}
+	if got != z.genFrom(0) {
+		t.Errorf("genExplainFrom(0) returned %v, genFrom %v", got, z.genFrom(0))
+	}
+}
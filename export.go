@@ -0,0 +1,114 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+)
+
+// exportTop is the number of items listed in the "top" field of ExportJSON.
+const exportTop = 10
+
+type jsonExport struct {
+	N             int           `json:"n"`
+	Entropy       float64       `json:"entropy"`
+	Labels        []string      `json:"labels,omitempty"`
+	Probabilities []float64     `json:"probabilities"`
+	Top           []jsonTopItem `json:"top"`
+}
+
+type jsonTopItem struct {
+	Index       uint32  `json:"index"`
+	Label       string  `json:"label,omitempty"`
+	Probability float64 `json:"probability"`
+}
+
+// ExportJSON writes a self-describing JSON summary of the table's
+// distribution, meant to be fed to dashboards and charts. It looks like
+//
+//	{
+//	  "n": 3,
+//	  "entropy": 1.5,
+//	  "probabilities": [0.25, 0.5, 0.25],
+//	  "top": [{"index": 1, "probability": 0.5}, ...]
+//	}
+//
+// where entropy is in bits, probabilities are those returned by Prob, and
+// top lists up to the ten most likely items in decreasing order.
+func (al *Alias) ExportJSON(w io.Writer) error {
+	return exportJSON(w, al.pmf(), nil)
+}
+
+// ExportJSON writes the same JSON as Alias.ExportJSON, with an extra
+// "labels" field holding the keys, and a "label" field on each top item.
+func (k *Keyed) ExportJSON(w io.Writer) error {
+	return exportJSON(w, k.al.pmf(), k.keys)
+}
+
+func exportJSON(w io.Writer, pmf []float64, labels []string) error {
+	e := jsonExport{
+		N:             len(pmf),
+		Entropy:       entropy(pmf),
+		Labels:        labels,
+		Probabilities: pmf,
+	}
+
+	for _, i := range topItems(pmf, exportTop) {
+		item := jsonTopItem{Index: i, Probability: pmf[i]}
+		if labels != nil {
+			item.Label = labels[i]
+		}
+		e.Top = append(e.Top, item)
+	}
+
+	return json.NewEncoder(w).Encode(e)
+}
+
+// Fingerprint returns a short string identifying the table's contents: the
+// first 16 bytes of the SHA-256 hash of its zero Layout encoding, in hex.
+// Tables with the same fingerprint draw identically from the same random
+// values, so it can confirm which table a process is using.
+func (al *Alias) Fingerprint() string {
+	sum := sha256.Sum256(al.MarshalLayout(Layout{}))
+	return hex.EncodeToString(sum[:16])
+}
+
+// entropy returns the Shannon entropy of pmf in bits.
+func entropy(pmf []float64) float64 {
+	h := float64(0)
+	for _, p := range pmf {
+		if p > 0 {
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+// topItems returns the indices of the k most likely items, most likely
+// first, breaking ties by index. It returns nil if k <= 0.
+func topItems(pmf []float64, k int) []uint32 {
+	if k <= 0 {
+		return nil
+	}
+
+	idx := make([]uint32, len(pmf))
+	for i := range idx {
+		idx[i] = uint32(i)
+	}
+
+	sort.SliceStable(idx, func(a, b int) bool {
+		return pmf[idx[a]] > pmf[idx[b]]
+	})
+
+	if k < len(idx) {
+		idx = idx[:k]
+	}
+	return idx
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExportJSON(t *testing.T) {
+	k, err := NewKeyed([]string{"a", "b", "c"}, []float64{1, 2, 1}, RejectDuplicates)
+	if err != nil {
+		t.Fatalf("Couldn't create keyed alias: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := k.ExportJSON(&buf); err != nil {
+		t.Fatalf("Couldn't ExportJSON: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("ExportJSON wrote invalid JSON: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"n":             3.0,
+		"entropy":       1.5,
+		"labels":        []interface{}{"a", "b", "c"},
+		"probabilities": []interface{}{0.25, 0.5, 0.25},
+		"top": []interface{}{
+			map[string]interface{}{"index": 1.0, "label": "b", "probability": 0.5},
+			map[string]interface{}{"index": 0.0, "label": "a", "probability": 0.25},
+			map[string]interface{}{"index": 2.0, "label": "c", "probability": 0.25},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExportJSON wrote %s", buf.Bytes())
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 3})
+	b := mustNew(t, []float64{2, 4, 6})
+	c := mustNew(t, []float64{3, 2, 1})
+
+	if len(a.Fingerprint()) != 32 {
+		t.Errorf("Fingerprint was %q", a.Fingerprint())
+	}
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("Equal tables have fingerprints %v and %v", a.Fingerprint(), b.Fingerprint())
+	}
+	if a.Fingerprint() == c.Fingerprint() {
+		t.Errorf("Different tables have the same fingerprint")
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// FairQueue schedules work among tenants in proportion to their weights,
+// deterministically: over any stretch of time, each busy tenant gets a
+// share of the work close to its share of the busy tenants' weight.
+//
+// Each tenant has a credit counter, as in deficit round robin. Every call
+// to NextTenant adds each busy tenant's weight to its credit and picks the
+// busy tenant with the most credit, as in smooth weighted round robin, so
+// a tenant's turns are spread evenly rather than bunched. The chosen tenant
+// is charged one unit of work, paid for with the total busy weight; work
+// that turns out to be bigger can be charged with Charge, so tenants with
+// costly work get fewer turns.
+//
+// A FairQueue is safe for concurrent use.
+type FairQueue struct {
+	mu      sync.Mutex
+	tenants []fqTenant
+	index   map[string]int
+}
+
+type fqTenant struct {
+	name   string
+	weight float64
+	credit float64
+	busy   bool
+}
+
+// NewFairQueue returns a FairQueue over the tenants in weights, all busy.
+// Weights must be positive. Ties in credit go to the tenant whose name
+// sorts first.
+func NewFairQueue(weights map[string]float64) (*FairQueue, error) {
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	q := &FairQueue{index: make(map[string]int, len(names))}
+	for i, name := range names {
+		if !(weights[name] > 0) {
+			return nil, errors.New("a probability is non-positive")
+		}
+		q.tenants = append(q.tenants, fqTenant{name: name, weight: weights[name], busy: true})
+		q.index[name] = i
+	}
+	return q, nil
+}
+
+// SetBusy marks whether tenant has work waiting. Idle tenants are skipped,
+// and their credit is reset, so a tenant can't save up turns while idle.
+func (q *FairQueue) SetBusy(tenant string, busy bool) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i, ok := q.index[tenant]
+	if !ok {
+		return errors.New("unknown tenant " + tenant)
+	}
+	if !busy {
+		q.tenants[i].credit = 0
+	}
+	q.tenants[i].busy = busy
+	return nil
+}
+
+// NextTenant returns the tenant whose work should be done next, and charges
+// it one unit. It returns false if no tenant is busy.
+func (q *FairQueue) NextTenant() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	best := -1
+	total := float64(0)
+	for i := range q.tenants {
+		t := &q.tenants[i]
+		if !t.busy {
+			continue
+		}
+		t.credit += t.weight
+		total += t.weight
+		if best < 0 || t.credit > q.tenants[best].credit {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+
+	q.tenants[best].credit -= total
+	return q.tenants[best].name, true
+}
+
+// Charge charges tenant for units more units of work, beyond the one
+// NextTenant charged, delaying its later turns accordingly. It's as if
+// units more turns went by, all of them taken by tenant.
+func (q *FairQueue) Charge(tenant string, units float64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i, ok := q.index[tenant]
+	if !ok {
+		return errors.New("unknown tenant " + tenant)
+	}
+
+	total := float64(0)
+	for j := range q.tenants {
+		t := &q.tenants[j]
+		if t.busy {
+			t.credit += units * t.weight
+			total += t.weight
+		}
+	}
+	q.tenants[i].credit -= units * total
+	return nil
+}
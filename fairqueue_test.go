@@ -0,0 +1,73 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFairQueue(t *testing.T) {
+	q, err := NewFairQueue(map[string]float64{"a": 5, "b": 1, "c": 1})
+	if err != nil {
+		t.Fatalf("Couldn't create FairQueue: %v", err)
+	}
+
+	// smooth: a's turns are spread out
+	var order []string
+	for i := 0; i < 7; i++ {
+		tenant, ok := q.NextTenant()
+		if !ok {
+			t.Fatalf("NextTenant failed with busy tenants")
+		}
+		order = append(order, tenant)
+	}
+	if got := strings.Join(order, ""); got != "aabacaa" {
+		t.Errorf("Order was %v, wanted aabacaa", got)
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 700; i++ {
+		tenant, _ := q.NextTenant()
+		counts[tenant]++
+	}
+	if counts["a"] != 500 || counts["b"] != 100 || counts["c"] != 100 {
+		t.Errorf("Counts were %v, wanted 500/100/100", counts)
+	}
+
+	// costly work gets fewer turns: a's work costs five units, so it gets
+	// as many turns as b and c
+	counts = make(map[string]int)
+	for i := 0; i < 699; i++ {
+		tenant, _ := q.NextTenant()
+		if tenant == "a" {
+			q.Charge("a", 4)
+		}
+		counts[tenant]++
+	}
+	if counts["a"] != 233 || counts["b"] != 233 || counts["c"] != 233 {
+		t.Errorf("Counts with charges were %v, wanted 233 each", counts)
+	}
+
+	// idle tenants are skipped
+	q.SetBusy("a", false)
+	q.SetBusy("b", false)
+	for i := 0; i < 10; i++ {
+		if tenant, _ := q.NextTenant(); tenant != "c" {
+			t.Fatalf("NextTenant returned idle tenant %v", tenant)
+		}
+	}
+	q.SetBusy("c", false)
+	if tenant, ok := q.NextTenant(); ok {
+		t.Errorf("NextTenant returned %v with no busy tenants", tenant)
+	}
+
+	if err := q.SetBusy("d", true); err == nil {
+		t.Errorf("SetBusy of an unknown tenant did not fail")
+	}
+	if _, err := NewFairQueue(map[string]float64{"a": 0}); err == nil {
+		t.Errorf("NewFairQueue with a zero weight did not fail")
+	}
+}
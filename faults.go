@@ -0,0 +1,43 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "math/rand"
+
+// Faults picks which fault, if any, to inject at a point in the code, for
+// chaos testing. For example,
+//
+//	f, err := alias.NewFaults(map[string]float64{
+//		"ok":      98,
+//		"timeout": 1.5,
+//		"error":   0.5,
+//	})
+//	...
+//	switch f.Roll(rng) {
+//	case "timeout":
+//		time.Sleep(time.Minute)
+//	case "error":
+//		return errInjected
+//	}
+type Faults struct {
+	k *Keyed
+}
+
+// NewFaults returns a Faults picking each action with probability
+// proportional to its weight. Weights must be positive. Include an action
+// for injecting nothing, carrying most of the weight.
+func NewFaults(actions map[string]float64) (*Faults, error) {
+	// sorted, so the same seed rolls the same actions every run
+	k, err := NewKeyedMap(actions)
+	if err != nil {
+		return nil, err
+	}
+	return &Faults{k}, nil
+}
+
+// Roll returns the action to take.
+func (f *Faults) Roll(rng *rand.Rand) string {
+	return f.k.Gen(rng)
+}
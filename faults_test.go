@@ -0,0 +1,26 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFaults(t *testing.T) {
+	f, err := NewFaults(map[string]float64{"ok": 90, "timeout": 7, "error": 3})
+	if err != nil {
+		t.Fatalf("Couldn't create Faults: %v", err)
+	}
+
+	index := map[string]uint32{"error": 0, "ok": 1, "timeout": 2}
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		return index[f.Roll(rng)]
+	}, []float64{3, 90, 7}, 1)
+
+	if _, err := NewFaults(map[string]float64{"ok": 1, "error": 0}); err == nil {
+		t.Errorf("NewFaults with a zero weight did not fail")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+//go:build (linux || darwin || freebsd || netbsd || openbsd || dragonfly) && !tinygo
+
+package alias
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// BuildFile builds the table for prob, as New does, directly into a memory
+// mapped file at path, in the headerless v1 format, the zero Layout. The
+// table is never held in heap memory, so tables larger than the heap can be
+// built; the file can be loaded later with UnmarshalBinary or read in place.
+//
+// Construction still needs 16 bytes of scratch memory per item.
+func BuildFile(path string, prob []float64) error {
+	total, err := checkProb(prob, false)
+	if err != nil {
+		return err
+	}
+
+	size := uint64(len(prob)) * 8
+	if uint64(int(size)) != size {
+		return errors.New("table too large for this platform")
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(int64(size)); err != nil {
+		return err
+	}
+
+	mem, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+
+	table := unsafe.Slice((*ipiece)(unsafe.Pointer(&mem[0])), len(prob))
+	fill(table, prob, total, vose)
+
+	// the file format is little endian, but the table was written in the
+	// native order
+	if binary.NativeEndian.Uint16([]byte{1, 0}) != 1 {
+		for i := range table {
+			bin := mem[i*8 : 8+i*8]
+			piece := table[i]
+			binary.LittleEndian.PutUint32(bin[0:4], piece.prob)
+			binary.LittleEndian.PutUint32(bin[4:8], piece.alias)
+		}
+	}
+
+	if err := syscall.Munmap(mem); err != nil {
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
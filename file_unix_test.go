@@ -0,0 +1,45 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+//go:build (linux || darwin || freebsd || netbsd || openbsd || dragonfly) && !tinygo
+
+package alias
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildFile(t *testing.T) {
+	prob := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 1000}
+	path := filepath.Join(t.TempDir(), "table")
+
+	if err := BuildFile(path, prob); err != nil {
+		t.Fatalf("Couldn't BuildFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Couldn't read table: %v", err)
+	}
+
+	a := &Alias{}
+	if err := a.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Couldn't UnmarshalBinary: %v", err)
+	}
+
+	want, err := New(prob)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	if !reflect.DeepEqual(a, want) {
+		t.Errorf("BuildFile wrote a different table than New builds")
+	}
+
+	if err := BuildFile(path, nil); err == nil {
+		t.Errorf("BuildFile with no probabilities did not fail")
+	}
+}
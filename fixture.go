@@ -0,0 +1,111 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
+)
+
+// Fixtures fills struct fields with weighted random values, for generating
+// realistic synthetic data in tests. For example,
+//
+//	f := alias.NewFixtures()
+//	f.Register("Status", map[string]float64{"active": 90, "suspended": 9, "banned": 1})
+//	f.Register("Age", map[string]float64{"25": 3, "40": 2, "70": 1})
+//
+//	var u User
+//	f.Fill(rng, &u)
+//
+// Values are given as strings and drawn by a keyed table, then converted
+// to the type of the field: strings as they are, booleans and numbers as
+// parsed by strconv, and any type implementing encoding.TextUnmarshaler by
+// its UnmarshalText method.
+type Fixtures struct {
+	fields map[string]*Keyed
+	order  []string // registration order, so Fill draws reproducibly
+}
+
+// NewFixtures returns a Fixtures with no fields registered.
+func NewFixtures() *Fixtures {
+	return &Fixtures{fields: make(map[string]*Keyed)}
+}
+
+// Register sets the values for the field named field, with their weights,
+// replacing any registered before. Weights must be positive.
+func (f *Fixtures) Register(field string, values map[string]float64) error {
+	k, err := NewKeyedMap(values)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.fields[field]; !ok {
+		f.order = append(f.order, field)
+	}
+	f.fields[field] = k
+	return nil
+}
+
+// Fill sets every registered field of the struct v points to, leaving its
+// other fields alone. It fails if v isn't a pointer to a struct, or a
+// registered field is missing, unexported, or can't hold a drawn value.
+func (f *Fixtures) Fill(rng *rand.Rand, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("fixture target is not a pointer to a struct")
+	}
+	rv = rv.Elem()
+
+	for _, name := range f.order {
+		field := rv.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() {
+			return errors.New("no settable field " + name)
+		}
+		if err := setFixture(field, f.fields[name].Gen(rng)); err != nil {
+			return errors.New("field " + name + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+func setFixture(field reflect.Value, s string) error {
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(x)
+	default:
+		return errors.New("unsupported type " + field.Type().String())
+	}
+	return nil
+}
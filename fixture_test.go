@@ -0,0 +1,68 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type fixtureUser struct {
+	Status  string
+	Age     uint8
+	Score   float64
+	Admin   bool
+	Created time.Time
+	Other   int
+	hidden  int
+}
+
+func TestFixtures(t *testing.T) {
+	f := NewFixtures()
+	must := func(err error) {
+		if err != nil {
+			t.Fatalf("Couldn't register: %v", err)
+		}
+	}
+	must(f.Register("Status", map[string]float64{"active": 90, "suspended": 9, "banned": 1}))
+	must(f.Register("Age", map[string]float64{"25": 1, "40": 1}))
+	must(f.Register("Score", map[string]float64{"0.5": 1}))
+	must(f.Register("Admin", map[string]float64{"true": 1, "false": 3}))
+	must(f.Register("Created", map[string]float64{"2015-01-02T03:04:05Z": 1}))
+
+	index := map[string]uint32{"active": 0, "suspended": 1, "banned": 2}
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		u := fixtureUser{Other: 7}
+		if err := f.Fill(rng, &u); err != nil {
+			t.Fatalf("Couldn't Fill: %v", err)
+		}
+		if u.Age != 25 && u.Age != 40 || u.Score != 0.5 || u.Other != 7 ||
+			!u.Created.Equal(time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Fatalf("Fill gave %+v", u)
+		}
+		return index[u.Status]
+	}, []float64{90, 9, 1}, 1)
+
+	rng := rand.New(rand.NewSource(1))
+	var u fixtureUser
+	if err := f.Fill(rng, u); err == nil {
+		t.Errorf("Fill of a non-pointer did not fail")
+	}
+
+	for _, field := range []string{"Missing", "hidden"} {
+		g := NewFixtures()
+		must(g.Register(field, map[string]float64{"1": 1}))
+		if err := g.Fill(rng, &u); err == nil {
+			t.Errorf("Fill of field %v did not fail", field)
+		}
+	}
+
+	g := NewFixtures()
+	must(g.Register("Age", map[string]float64{"300": 1}))
+	if err := g.Fill(rng, &u); err == nil {
+		t.Errorf("Fill with an out of range value did not fail")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// formatMagic starts the v2 binary format, described at MarshalBinary. The
+// v1 format, which earlier versions wrote, is the slots alone. The last byte
+// of the magic has its top bit set, which the last byte of the first slot's
+// threshold never does, so the two can't be confused.
+var formatMagic = [4]byte{'A', 'L', 'S', 0xA1}
+
+const (
+	formatVersion = 2
+	formatHeader  = 12 // bytes before the slots
+	formatFooter  = 4  // bytes after them
+)
+
+// isV2 reports whether p starts with the v2 magic.
+func isV2(p []byte) bool {
+	return len(p) >= 4 && [4]byte(p[:4]) == formatMagic
+}
+
+// appendHeader appends the v2 header for a table of n slots to b.
+func appendHeader(b []byte, n int) []byte {
+	b = append(b, formatMagic[:]...)
+	b = binary.LittleEndian.AppendUint32(b, formatVersion)
+	return binary.LittleEndian.AppendUint32(b, uint32(n))
+}
+
+// parseHeader checks a v2 header and returns the number of slots.
+func parseHeader(h []byte) (int, error) {
+	if !isV2(h) {
+		return 0, errors.New("bad data: bad magic")
+	}
+	if v := binary.LittleEndian.Uint32(h[4:]); v != formatVersion {
+		return 0, errors.New("bad data: unsupported version")
+	}
+	return int(binary.LittleEndian.Uint32(h[8:])), nil
+}
+
+// marshalV2 encodes the table in the v2 format.
+func (al *Alias) marshalV2() []byte {
+	out := make([]byte, 0, formatHeader+8*len(al.table)+formatFooter)
+	out = appendHeader(out, len(al.table))
+	out = append(out, al.MarshalLayout(Layout{})...)
+	return binary.LittleEndian.AppendUint32(out, crc32.ChecksumIEEE(out))
+}
+
+// unmarshalAny decodes a table in the v2 or v1 format.
+func (al *Alias) unmarshalAny(p []byte) error {
+	if !isV2(p) {
+		return al.UnmarshalLayout(p, Layout{})
+	}
+
+	if len(p) < formatHeader+formatFooter {
+		return errors.New("bad data length")
+	}
+	n, err := parseHeader(p)
+	if err != nil {
+		return err
+	}
+	if uint64(len(p)) != formatHeader+8*uint64(n)+formatFooter {
+		return errors.New("bad data length")
+	}
+
+	body := p[:len(p)-formatFooter]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(p[len(body):]) {
+		return errors.New("bad data: checksum mismatch")
+	}
+
+	return al.UnmarshalLayout(body[formatHeader:], Layout{})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestFormatV2(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 3, 4})
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Couldn't MarshalBinary: %v", err)
+	}
+
+	if !bytes.Equal(data[:4], []byte{'A', 'L', 'S', 0xA1}) {
+		t.Errorf("Data starts with %v, not the magic", data[:4])
+	}
+	if v := binary.LittleEndian.Uint32(data[4:]); v != 2 {
+		t.Errorf("Version was %v, wanted 2", v)
+	}
+	if n := binary.LittleEndian.Uint32(data[8:]); n != 4 {
+		t.Errorf("Count was %v, wanted 4", n)
+	}
+
+	// v1 data still reads
+	a2 := &Alias{}
+	if err := a2.UnmarshalBinary(a.MarshalLayout(Layout{})); err != nil {
+		t.Fatalf("Couldn't UnmarshalBinary v1 data: %v", err)
+	}
+	if !reflect.DeepEqual(a, a2) {
+		t.Fatalf("Unmarshalled v1 version was not the same as original")
+	}
+
+	// every single bit flip is caught
+	for i := range data {
+		for bit := 0; bit < 8; bit++ {
+			p := append([]byte(nil), data...)
+			p[i] ^= 1 << bit
+			if err := a2.UnmarshalBinary(p); err == nil {
+				t.Fatalf("UnmarshalBinary with bit %v of byte %v flipped did not fail", bit, i)
+			}
+		}
+	}
+
+	for i := 4; i < len(data); i++ {
+		if err := a2.UnmarshalBinary(data[:i]); err == nil {
+			t.Errorf("UnmarshalBinary of %v truncated bytes did not fail", len(data)-i)
+		}
+	}
+
+	// an unknown version
+	p := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(p[4:], 3)
+	if err := a2.UnmarshalBinary(p); err == nil {
+		t.Errorf("UnmarshalBinary of version 3 did not fail")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "errors"
+
+// Create a new alias object over items assigned to groups. Item i belongs to
+// group groups[i]. The total probability of each group g is proportional to
+// groupWeights[g], and within a group items are picked in proportion to
+// itemWeights. For example,
+//
+//	var v = alias.NewGrouped([]uint32{0, 0, 1}, []float64{1, 3, 5}, []float64{1, 1})
+//
+// creates an alias that returns 0 12.5% of the time, 1 37.5% of the time, and
+// 2 50% of the time.
+//
+// Picking a group and then an item within it is the same as picking the item
+// with the product of the two probabilities, so the result is an ordinary
+// table and Gen costs no more than for New.
+func NewGrouped(groups []uint32, itemWeights, groupWeights []float64) (*Alias, error) {
+	if len(groups) != len(itemWeights) {
+		return nil, errors.New("groups and item weights have different lengths")
+	}
+
+	groupTotals := make([]float64, len(groupWeights))
+	for i, g := range groups {
+		if int(g) >= len(groupWeights) {
+			return nil, errors.New("group out of range")
+		}
+		if itemWeights[i] <= 0 {
+			return nil, errors.New("a probability is non-positive")
+		}
+		groupTotals[g] += itemWeights[i]
+	}
+
+	for g, w := range groupWeights {
+		if w <= 0 {
+			return nil, errors.New("a group probability is non-positive")
+		}
+		if groupTotals[g] == 0 {
+			return nil, errors.New("a group has no items")
+		}
+	}
+
+	prob := make([]float64, len(itemWeights))
+	for i, g := range groups {
+		prob[i] = groupWeights[g] * itemWeights[i] / groupTotals[g]
+	}
+
+	return New(prob)
+}
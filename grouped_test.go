@@ -0,0 +1,22 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "testing"
+
+func TestNewGrouped(t *testing.T) {
+	a, err := NewGrouped([]uint32{0, 1, 0, 1, 2}, []float64{1, 1, 3, 4, 7}, []float64{2, 1, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	checkDistribution(t, a.Gen, []float64{0.125, 0.05, 0.375, 0.2, 0.25}, 1)
+
+	if _, err := NewGrouped([]uint32{0, 0}, []float64{1, 1}, []float64{1, 1}); err == nil {
+		t.Errorf("NewGrouped with an empty group did not fail")
+	}
+	if _, err := NewGrouped([]uint32{0, 2}, []float64{1, 1}, []float64{1, 1}); err == nil {
+		t.Errorf("NewGrouped with an out of range group did not fail")
+	}
+}
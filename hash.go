@@ -0,0 +1,66 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// GenHash picks an index deterministically from a 64-bit hash value, with
+// the same distribution as Gen if the hash values are uniformly distributed.
+// Only the top 31 bits of h are used.
+func (al *Alias) GenHash(h uint64) uint32 {
+	return al.genFrom(uint32(h >> 33))
+}
+
+// GenKey picks an index deterministically from a key, so the same key always
+// gets the same index from the same table. The key is hashed with hash, or
+// with 64-bit FNV-1a if hash is nil.
+//
+// Passing a hash lets the choice line up with an existing bucketing scheme.
+// For example, to use hash/maphash with a fixed seed:
+//
+//	a.GenKey(key, func(b []byte) uint64 { return maphash.Bytes(seed, b) })
+func (al *Alias) GenKey(key []byte, hash func([]byte) uint64) uint32 {
+	if hash == nil {
+		hash = fnv1a
+	}
+	return al.GenHash(hash(key))
+}
+
+// fnv1a is 64-bit FNV-1a, written out so that it doesn't allocate.
+func fnv1a(b []byte) uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+
+	h := uint64(offset)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= prime
+	}
+	return h
+}
+
+// GenEpoch picks an index deterministically from a key and an epoch, such as
+// a user ID and a day number, so each key gets a stable pick within an epoch
+// and an independent one in the next, without storing assignments.
+//
+// The pick is derived from SHA-256 of the key followed by the epoch as a
+// little endian uint64, and will not change between versions of this
+// package.
+func (al *Alias) GenEpoch(key []byte, epoch uint64) uint32 {
+	h := sha256.New()
+	h.Write(key)
+
+	var e [8]byte
+	binary.LittleEndian.PutUint64(e[:], epoch)
+	h.Write(e[:])
+
+	var sum [sha256.Size]byte
+	return al.GenHash(binary.BigEndian.Uint64(h.Sum(sum[:0])))
+}
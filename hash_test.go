@@ -0,0 +1,65 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/binary"
+	"hash/crc64"
+	"math/rand"
+	"testing"
+)
+
+func TestGenKey(t *testing.T) {
+	a, err := New([]float64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	key := make([]byte, 8)
+	keyed := func(hash func([]byte) uint64) func(*rand.Rand) uint32 {
+		return func(rng *rand.Rand) uint32 {
+			binary.LittleEndian.PutUint64(key, rng.Uint64())
+			i := a.GenKey(key, hash)
+			if j := a.GenKey(key, hash); j != i {
+				t.Fatalf("GenKey gave %v and then %v for the same key", i, j)
+			}
+			return i
+		}
+	}
+
+	checkDistribution(t, keyed(nil), []float64{1, 2, 3}, 1)
+
+	table := crc64.MakeTable(crc64.ECMA)
+	checkDistribution(t, keyed(func(b []byte) uint64 {
+		return crc64.Checksum(b, table)
+	}), []float64{1, 2, 3}, 2)
+}
+
+func TestGenEpoch(t *testing.T) {
+	dist := []float64{1, 2, 3}
+	a, err := New(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	key := []byte("user")
+	epoch := uint64(0)
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		epoch++
+		i := a.GenEpoch(key, epoch)
+		if j := a.GenEpoch(key, epoch); j != i {
+			t.Fatalf("GenEpoch gave %v and then %v for the same epoch", i, j)
+		}
+		return i
+	}, dist, 1)
+
+	// pinned, since the derivation is promised to be stable
+	want := []uint32{2, 2, 2, 2, 0, 1, 2, 2}
+	for e, w := range want {
+		if got := a.GenEpoch(key, uint64(e)); got != w {
+			t.Errorf("GenEpoch(%q, %v) was %v, wanted %v", key, e, got, w)
+		}
+	}
+}
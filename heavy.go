@@ -0,0 +1,145 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+// HeavyHitters tracks the most frequent keys of a weighted stream in a fixed
+// amount of memory, by the Space-Saving algorithm of Metwally, Agrawal and
+// El Abbadi, so a distribution can be built from what's popular right now.
+//
+// It keeps counts for at most capacity keys. A new key arriving when it's
+// full takes the place of the key with the smallest count, and inherits
+// that count as its possible overestimate. Any key whose true count is more
+// than 1/capacity of the stream's total is sure to be tracked.
+//
+// A HeavyHitters is safe for concurrent use.
+type HeavyHitters struct {
+	mu       sync.Mutex
+	capacity int
+	entries  hhHeap
+}
+
+// HeavyHitter is a tracked key. Its true count is between Count-Error and
+// Count.
+type HeavyHitter struct {
+	Key   string
+	Count uint64
+	Error uint64
+}
+
+// NewHeavyHitters returns a HeavyHitters that tracks up to capacity keys.
+func NewHeavyHitters(capacity int) (*HeavyHitters, error) {
+	if capacity < 1 {
+		return nil, errors.New("capacity must be positive")
+	}
+	return &HeavyHitters{
+		capacity: capacity,
+		entries:  hhHeap{index: make(map[string]int, capacity)},
+	}, nil
+}
+
+// Add records weight occurrences of key. Counts saturate rather than wrap.
+func (h *HeavyHitters) Add(key string, weight uint64) {
+	if weight == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if i, ok := h.entries.index[key]; ok {
+		h.entries.items[i].Count = addSat(h.entries.items[i].Count, weight)
+		heap.Fix(&h.entries, i)
+		return
+	}
+
+	if len(h.entries.items) < h.capacity {
+		heap.Push(&h.entries, HeavyHitter{key, weight, 0})
+		return
+	}
+
+	// replace the smallest
+	min := h.entries.items[0]
+	delete(h.entries.index, min.Key)
+	h.entries.items[0] = HeavyHitter{key, addSat(min.Count, weight), min.Count}
+	h.entries.index[key] = 0
+	heap.Fix(&h.entries, 0)
+}
+
+func addSat(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
+
+// Top returns up to k tracked keys, largest count first, with ties broken
+// by key.
+func (h *HeavyHitters) Top(k int) []HeavyHitter {
+	h.mu.Lock()
+	top := append([]HeavyHitter(nil), h.entries.items...)
+	h.mu.Unlock()
+
+	sort.Slice(top, func(a, b int) bool {
+		if top[a].Count != top[b].Count {
+			return top[a].Count > top[b].Count
+		}
+		return top[a].Key < top[b].Key
+	})
+
+	if k < len(top) {
+		top = top[:k]
+	}
+	return top
+}
+
+// Table returns a Keyed table over the tracked keys, weighted by their
+// counts, with keys in the order Top returns them.
+func (h *HeavyHitters) Table() (*Keyed, error) {
+	top := h.Top(h.capacity)
+
+	keys := make([]string, len(top))
+	weights := make([]float64, len(top))
+	for i, hh := range top {
+		keys[i] = hh.Key
+		weights[i] = float64(hh.Count)
+	}
+	return NewKeyed(keys, weights, RejectDuplicates)
+}
+
+// hhHeap is a min-heap of counts, with the position of each key in items.
+type hhHeap struct {
+	items []HeavyHitter
+	index map[string]int
+}
+
+func (hh *hhHeap) Len() int           { return len(hh.items) }
+func (hh *hhHeap) Less(a, b int) bool { return hh.items[a].Count < hh.items[b].Count }
+
+func (hh *hhHeap) Swap(a, b int) {
+	hh.items[a], hh.items[b] = hh.items[b], hh.items[a]
+	hh.index[hh.items[a].Key] = a
+	hh.index[hh.items[b].Key] = b
+}
+
+func (hh *hhHeap) Push(x any) {
+	item := x.(HeavyHitter)
+	hh.index[item.Key] = len(hh.items)
+	hh.items = append(hh.items, item)
+}
+
+func (hh *hhHeap) Pop() any {
+	item := hh.items[len(hh.items)-1]
+	hh.items = hh.items[:len(hh.items)-1]
+	delete(hh.index, item.Key)
+	return item
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestHeavyHitters(t *testing.T) {
+	h, err := NewHeavyHitters(10)
+	if err != nil {
+		t.Fatalf("Couldn't create HeavyHitters: %v", err)
+	}
+
+	// three heavy keys in a stream of many light ones
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		switch i % 10 {
+		case 0, 1, 2:
+			h.Add("a", 1)
+		case 3:
+			h.Add("b", 2)
+		case 4:
+			h.Add("c", 1)
+		default:
+			h.Add(strconv.Itoa(rng.Intn(5000)), 1)
+		}
+	}
+
+	top := h.Top(3)
+	var keys []string
+	for _, hh := range top {
+		keys = append(keys, hh.Key)
+		if hh.Count < hh.Error {
+			t.Errorf("Key %v has count %v below its error %v", hh.Key, hh.Count, hh.Error)
+		}
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Fatalf("Top keys were %v, wanted a, b, c", keys)
+	}
+	want := []uint64{6000, 4000, 2000}
+	for i, hh := range top {
+		if hh.Count-hh.Error > want[i] || hh.Count < want[i] {
+			t.Errorf("Key %v has count %v (error %v), true count %v", hh.Key, hh.Count, hh.Error, want[i])
+		}
+	}
+
+	k, err := h.Table()
+	if err != nil {
+		t.Fatalf("Couldn't build table: %v", err)
+	}
+	if got := k.Keys(); len(got) != 10 || !reflect.DeepEqual(got[:3], keys) {
+		t.Errorf("Table keys were %v", got)
+	}
+
+	h.Add("a", math.MaxUint64)
+	if c := h.Top(1)[0].Count; c != math.MaxUint64 {
+		t.Errorf("Count was %v after overflow, wanted it saturated", c)
+	}
+
+	if _, err := NewHeavyHitters(0); err == nil {
+		t.Errorf("NewHeavyHitters with zero capacity did not fail")
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// Hybrid is a two-level sampler: a small "head" of weights that can be
+// changed cheaply sits in front of a static alias table "tail".
+//
+// Head items are numbered 0 through len(head)-1, and tail items follow them.
+// Changing a head weight costs O(len(head)), so the head should be kept
+// small (tens to hundreds of items); the tail may be arbitrarily large.
+//
+// A Hybrid is safe for concurrent use. Its state is copy-on-write: each
+// change publishes a complete new state, and each Gen uses the one state
+// that was current when it started, so concurrent draws never see a change
+// half made and never wait for one.
+type Hybrid struct {
+	mu    sync.Mutex // serializes changes
+	state atomic.Pointer[hybridState]
+}
+
+// hybridState is never modified once published.
+type hybridState struct {
+	head     []float64
+	headSum  float64
+	tail     *Alias
+	tailMass float64
+}
+
+// Create a new hybrid sampler. Head weights must be non-negative, tail
+// weights must be positive, as with New.
+func NewHybrid(head, tail []float64) (*Hybrid, error) {
+	for _, w := range head {
+		if err := checkHeadWeight(w); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := New(tail)
+	if err != nil {
+		return nil, err
+	}
+
+	tailMass := float64(0)
+	for _, w := range tail {
+		tailMass += w
+	}
+
+	h := &Hybrid{}
+	h.state.Store(newHybridState(append([]float64(nil), head...), t, tailMass))
+	return h, nil
+}
+
+func newHybridState(head []float64, tail *Alias, tailMass float64) *hybridState {
+	return &hybridState{
+		head:     head,
+		headSum:  sum(head),
+		tail:     tail,
+		tailMass: tailMass,
+	}
+}
+
+func checkHeadWeight(w float64) error {
+	if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+		return errors.New("a head weight is negative or not finite")
+	}
+	return nil
+}
+
+func sum(ws []float64) float64 {
+	total := float64(0)
+	for _, w := range ws {
+		total += w
+	}
+	return total
+}
+
+// Len returns the total number of items, head and tail.
+func (h *Hybrid) Len() int {
+	s := h.state.Load()
+	return len(s.head) + len(s.tail.table)
+}
+
+// SetHeadWeight changes the weight of head item i. A weight of zero removes
+// the item from consideration until it is given a positive weight again.
+func (h *Hybrid) SetHeadWeight(i int, w float64) error {
+	if err := checkHeadWeight(w); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.state.Load()
+	if i < 0 || i >= len(s.head) {
+		return errors.New("head index out of range")
+	}
+
+	head := append([]float64(nil), s.head...)
+	head[i] = w
+
+	// resum rather than adjusting, so floating point error can't accumulate
+	// over many updates
+	h.state.Store(newHybridState(head, s.tail, s.tailMass))
+
+	return nil
+}
+
+// SetHeadWeights changes all the head weights at once, so that no draw
+// sees some changed and others not. weights must have one entry per head
+// item.
+func (h *Hybrid) SetHeadWeights(weights []float64) error {
+	for _, w := range weights {
+		if err := checkHeadWeight(w); err != nil {
+			return err
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.state.Load()
+	if len(weights) != len(s.head) {
+		return errors.New("wrong number of head weights")
+	}
+
+	head := append([]float64(nil), weights...)
+	h.state.Store(newHybridState(head, s.tail, s.tailMass))
+
+	return nil
+}
+
+// Generates a random number according to the distribution using the rng passed.
+func (h *Hybrid) Gen(rng *rand.Rand) uint32 {
+	s := h.state.Load()
+
+	x := rng.Float64() * (s.headSum + s.tailMass)
+	if x < s.headSum {
+		for i, w := range s.head {
+			if x < w {
+				return uint32(i)
+			}
+			x -= w
+		}
+		// rounding error walked us off the end of the head; let the tail
+		// take it
+	}
+
+	return uint32(len(s.head)) + s.tail.Gen(rng)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaller. The current head
+// weights are saved along with the tail, so a restarted process can resume
+// with the same distribution.
+//
+// The format is the number of head items as a little endian uint32, the head
+// weights and then the total tail weight as little endian IEEE 754 doubles,
+// and then the tail as written by Alias.MarshalBinary.
+func (h *Hybrid) MarshalBinary() ([]byte, error) {
+	s := h.state.Load()
+
+	tail, err := s.tail.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 4+8*(len(s.head)+1)+len(tail))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(s.head)))
+	for _, w := range s.head {
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(w))
+	}
+	out = binary.LittleEndian.AppendUint64(out, math.Float64bits(s.tailMass))
+	out = append(out, tail...)
+
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaller.
+func (h *Hybrid) UnmarshalBinary(p []byte) error {
+	if len(p) < 4 {
+		return errors.New("bad data length")
+	}
+	n := binary.LittleEndian.Uint32(p)
+	p = p[4:]
+
+	if (uint64(n)+1)*8 > uint64(len(p)) {
+		return errors.New("bad data length")
+	}
+
+	head := make([]float64, n)
+	for i := range head {
+		head[i] = math.Float64frombits(binary.LittleEndian.Uint64(p))
+		p = p[8:]
+		if err := checkHeadWeight(head[i]); err != nil {
+			return errors.New("bad data: " + err.Error())
+		}
+	}
+
+	tailMass := math.Float64frombits(binary.LittleEndian.Uint64(p))
+	p = p[8:]
+	if !(tailMass > 0) || math.IsInf(tailMass, 0) {
+		return errors.New("bad data: tail weight out of range")
+	}
+
+	tail := &Alias{}
+	if err := tail.UnmarshalBinary(p); err != nil {
+		return err
+	}
+	if len(tail.table) == 0 {
+		return errors.New("bad data: empty tail")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.state.Store(newHybridState(head, tail, tailMass))
+
+	return nil
+}
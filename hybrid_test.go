@@ -0,0 +1,133 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestHybrid(t *testing.T) {
+	h, err := NewHybrid([]float64{5, 0}, []float64{1, 2, 2})
+	if err != nil {
+		t.Fatalf("Couldn't create hybrid: %v", err)
+	}
+	if h.Len() != 5 {
+		t.Fatalf("Len was %v, wanted 5", h.Len())
+	}
+	checkDistribution(t, h.Gen, []float64{5, 0, 1, 2, 2}, 3)
+
+	if err := h.SetHeadWeight(0, 1); err != nil {
+		t.Fatalf("Couldn't set head weight: %v", err)
+	}
+	if err := h.SetHeadWeight(1, 4); err != nil {
+		t.Fatalf("Couldn't set head weight: %v", err)
+	}
+	checkDistribution(t, h.Gen, []float64{1, 4, 1, 2, 2}, 4)
+
+	if err := h.SetHeadWeight(2, 1); err == nil {
+		t.Errorf("Setting a tail weight did not fail")
+	}
+	if err := h.SetHeadWeight(0, -1); err == nil {
+		t.Errorf("Setting a negative weight did not fail")
+	}
+}
+
+func TestHybridMarshalBinary(t *testing.T) {
+	h, err := NewHybrid([]float64{5, 0}, []float64{1, 2, 2})
+	if err != nil {
+		t.Fatalf("Couldn't create hybrid: %v", err)
+	}
+	if err := h.SetHeadWeight(1, 3); err != nil {
+		t.Fatalf("Couldn't set head weight: %v", err)
+	}
+
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Couldn't MarshalBinary: %v", err)
+	}
+
+	h2 := &Hybrid{}
+	if err := h2.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Couldn't UnmarshalBinary: %v", err)
+	}
+
+	checkDistribution(t, h2.Gen, []float64{5, 3, 1, 2, 2}, 6)
+
+	for i := 0; i < len(data); i++ {
+		if err := h2.UnmarshalBinary(data[:i]); err == nil {
+			t.Errorf("UnmarshalBinary of %v truncated bytes did not fail", len(data)-i)
+		}
+	}
+}
+
+func TestHybridSetHeadWeights(t *testing.T) {
+	h, err := NewHybrid([]float64{1, 1}, []float64{1})
+	if err != nil {
+		t.Fatalf("Couldn't create hybrid: %v", err)
+	}
+
+	if err := h.SetHeadWeights([]float64{3, 0}); err != nil {
+		t.Fatalf("Couldn't set head weights: %v", err)
+	}
+	checkDistribution(t, h.Gen, []float64{3, 0, 1}, 1)
+
+	if err := h.SetHeadWeights([]float64{1}); err == nil {
+		t.Errorf("Setting too few head weights did not fail")
+	}
+}
+
+// TestHybridSnapshot checks that draws racing with changes each see one
+// whole state. Run it with -race to check the internals too.
+func TestHybridSnapshot(t *testing.T) {
+	// the tail is so light that a draw should never land in it, unless it
+	// sees the head weights of one state and the head sum of the other
+	h, err := NewHybrid([]float64{1, 0}, []float64{1e-12})
+	if err != nil {
+		t.Fatalf("Couldn't create hybrid: %v", err)
+	}
+
+	stop := make(chan struct{})
+	var writer, readers sync.WaitGroup
+	writer.Add(1)
+	go func() {
+		defer writer.Done()
+		states := [][]float64{{1, 0}, {0, 3}}
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if err := h.SetHeadWeights(states[i%2]); err != nil {
+				t.Errorf("Couldn't set head weights: %v", err)
+				return
+			}
+
+			// without preemption, as under WebAssembly, let the readers run
+			runtime.Gosched()
+		}
+	}()
+
+	for g := 0; g < 4; g++ {
+		readers.Add(1)
+		go func(seed int64) {
+			defer readers.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < 20000; i++ {
+				if v := h.Gen(rng); v > 1 {
+					t.Errorf("Gen returned tail item %v", v)
+					return
+				}
+			}
+		}(int64(g))
+	}
+
+	readers.Wait()
+	close(stop)
+	writer.Wait()
+}
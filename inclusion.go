@@ -0,0 +1,51 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+)
+
+// InclusionPPS returns the probability that each index is included in a
+// sample of n drawn by GenPPS, for Horvitz-Thompson estimation. These are
+// exact.
+func (al *Alias) InclusionPPS(n int) ([]float64, error) {
+	return ppsInclusion(al.pmf(), al.pmfLive, n)
+}
+
+// InclusionDistinct estimates the probability that each index is among the
+// first k draws of a Draw session (as returned by GenDistinct with no
+// minimum mass), by running trials sessions. Successive sampling without
+// replacement has no closed form for these, so they are estimated; the
+// standard error of each is at most 0.5/sqrt(trials).
+func (al *Alias) InclusionDistinct(rng *rand.Rand, k, trials int) ([]float64, error) {
+	if k < 0 {
+		return nil, errors.New("sample size out of range")
+	}
+	if trials < 1 {
+		return nil, errors.New("trials must be positive")
+	}
+
+	counts := make([]float64, len(al.table))
+	withLabel(al.profileLabel, "InclusionDistinct", func(context.Context) {
+		for t := 0; t < trials; t++ {
+			d := al.BeginDraw()
+			for j := 0; j < k; j++ {
+				i, ok := d.Gen(rng)
+				if !ok {
+					break
+				}
+				counts[i]++
+			}
+		}
+	})
+
+	for i := range counts {
+		counts[i] /= float64(trials)
+	}
+	return counts, nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestInclusion(t *testing.T) {
+	a, err := New([]float64{6, 1, 2, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	pi, err := a.InclusionPPS(2)
+	if err != nil {
+		t.Fatalf("InclusionPPS failed: %v", err)
+	}
+	for i, w := range []float64{1, 0.25, 0.5, 0.25} {
+		if math.Abs(pi[i]-w) > 1e-8 {
+			t.Errorf("InclusionPPS(2)[%v] was %v, wanted %v", i, pi[i], w)
+		}
+	}
+
+	// for two draws, item 0 is included with probability
+	// 0.6 + 0.1*(6/9) + 0.2*(6/8) + 0.1*(6/9)
+	pi, err = a.InclusionDistinct(rand.New(rand.NewSource(1)), 2, 100000)
+	if err != nil {
+		t.Fatalf("InclusionDistinct failed: %v", err)
+	}
+	want := 0.6 + 0.1*6/9 + 0.2*6/8 + 0.1*6/9
+	if math.Abs(pi[0]-want) > 0.01 {
+		t.Errorf("InclusionDistinct(2)[0] was %v, wanted about %v", pi[0], want)
+	}
+	if s := sum(pi); math.Abs(s-2) > 1e-9 {
+		t.Errorf("InclusionDistinct(2) summed to %v", s)
+	}
+}
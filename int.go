@@ -0,0 +1,166 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/bits"
+)
+
+type upiece struct {
+	amount uint64
+	alias  uint32
+}
+
+// Create a new alias object from integer weights.
+//
+// Unlike New, the pairing of small and large items is done in exact integer
+// arithmetic: every weight is scaled by len(weights), so each slot holds
+// exactly the total weight and no rounding happens until the final
+// quantization of each slot's threshold. There is never any leftover mass
+// that needs a dummy slot, and the ratios between weights are kept exactly.
+//
+// All weights must be positive, and both their sum and each weight times
+// len(weights) must fit in a uint64; NewInt fails otherwise. NewIntScaled
+// accepts larger weights at a small cost in accuracy.
+//
+// The resulting table is an ordinary one: Gen on it is the same single,
+// never-retrying draw as on a table from New.
+func NewInt(weights []uint64) (*Alias, error) {
+	n := len(weights)
+
+	if n < 1 {
+		return nil, errors.New("too few probabilities")
+	}
+
+	if int(uint32(n)) != n {
+		return nil, errors.New("too many probabilities")
+	}
+
+	total := uint64(0)
+	for _, w := range weights {
+		if w == 0 {
+			return nil, errors.New("a weight is zero")
+		}
+		var carry uint64
+		total, carry = bits.Add64(total, w, 0)
+		if carry != 0 {
+			return nil, errors.New("weights sum past the range of uint64")
+		}
+	}
+
+	var al Alias
+	al.table = make([]ipiece, n)
+
+	// same twin stack layout as New
+	twins := make([]upiece, n)
+
+	smTop := -1
+	lgBot := n
+
+	for i, w := range weights {
+		hi, a := bits.Mul64(w, uint64(n))
+		if hi != 0 {
+			return nil, errors.New("a weight times the number of weights is past the range of uint64")
+		}
+
+		if a >= total {
+			lgBot--
+			twins[lgBot] = upiece{a, uint32(i)}
+		} else {
+			smTop++
+			twins[smTop] = upiece{a, uint32(i)}
+		}
+	}
+
+	for smTop >= 0 && lgBot < n {
+		l := twins[smTop]
+		smTop--
+
+		g := twins[lgBot]
+		lgBot++
+
+		al.table[l.alias].prob = quantizeInt(l.amount, total)
+		al.table[l.alias].alias = g.alias
+
+		// g.amount + l.amount - total, without overflowing
+		g.amount -= total - l.amount
+
+		if g.amount < total {
+			smTop++
+			twins[smTop] = g
+		} else {
+			lgBot--
+			twins[lgBot] = g
+		}
+	}
+
+	// the arithmetic is exact, so only full slots can be left over
+	for i := n - 1; i >= lgBot; i-- {
+		al.table[twins[i].alias].prob = 1<<31 - 1
+	}
+
+	return &al, nil
+}
+
+// Create a new alias object from integer weights of any size.
+//
+// If the weights are too large for NewInt, they're first scaled down by the
+// smallest power of two that makes them fit, with each rounded to the
+// nearest integer and any that would round to zero raised to one. The
+// largest weight is left at about 2^63/n or more, so each item's
+// probability moves by less than n^2/2^62, where n is len(weights). Weights
+// that already fit are used exactly, as by NewInt.
+func NewIntScaled(weights []uint64) (*Alias, error) {
+	n := uint64(len(weights))
+
+	max := uint64(0)
+	for _, w := range weights {
+		if w > max {
+			max = w
+		}
+	}
+
+	// every scaled weight is at most the scaled max, so if n of those fit,
+	// so do the sum and every product NewInt computes
+	k := uint(0)
+	for ; k < 64; k++ {
+		hi, _ := bits.Mul64(roundShift(max, k), n)
+		if hi == 0 {
+			break
+		}
+	}
+	if k == 0 {
+		return NewInt(weights)
+	}
+
+	scaled := make([]uint64, len(weights))
+	for i, w := range weights {
+		if w == 0 {
+			return nil, errors.New("a weight is zero")
+		}
+		scaled[i] = roundShift(w, k)
+		if scaled[i] == 0 {
+			scaled[i] = 1
+		}
+	}
+	return NewInt(scaled)
+}
+
+// roundShift returns w/2^k rounded to the nearest integer, for k < 64.
+func roundShift(w uint64, k uint) uint64 {
+	if k == 0 {
+		return w
+	}
+	return w>>k + w>>(k-1)&1
+}
+
+// quantizeInt returns amount/total scaled to [0,2^31-1). amount must be less
+// than total.
+func quantizeInt(amount, total uint64) uint32 {
+	hi, lo := bits.Mul64(amount, 1<<31-1)
+	q, _ := bits.Div64(hi, lo, total)
+	return uint32(q)
+}
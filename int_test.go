@@ -0,0 +1,78 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewInt(t *testing.T) {
+	tests := [][]uint64{
+		{1},
+		{1, 1},
+		{1, 2, 3},
+		{9, 8, 1, 4, 2},
+		{1000, 1, 3, 10},
+	}
+	for i, weights := range tests {
+		a, err := NewInt(weights)
+		if err != nil {
+			t.Fatalf("Couldn't create alias: %v", err)
+		}
+
+		dist := make([]float64, len(weights))
+		for j, w := range weights {
+			dist[j] = float64(w)
+		}
+		checkDistribution(t, a.Gen, dist, int64(i))
+	}
+}
+
+func TestNewIntErrors(t *testing.T) {
+	tests := [][]uint64{
+		{},
+		{1, 0},
+		{math.MaxUint64, 1},
+		{math.MaxUint64/2 + 1, 1},
+	}
+	for _, weights := range tests {
+		if _, err := NewInt(weights); err == nil {
+			t.Errorf("NewInt(%v) did not fail", weights)
+		}
+	}
+}
+
+func TestNewIntScaled(t *testing.T) {
+	tests := [][]uint64{
+		{math.MaxUint64, math.MaxUint64 / 3, math.MaxUint64 / 6},
+		{math.MaxUint64/2 + 1, 1 << 62},
+		{3, 1, 2},
+	}
+	for i, weights := range tests {
+		a, err := NewIntScaled(weights)
+		if err != nil {
+			t.Fatalf("NewIntScaled(%v) failed: %v", weights, err)
+		}
+
+		dist := make([]float64, len(weights))
+		for j, w := range weights {
+			dist[j] = float64(w)
+		}
+		checkDistribution(t, a.Gen, dist, int64(i))
+	}
+
+	// a tiny weight next to a huge one is raised rather than rounded to zero
+	if _, err := NewIntScaled([]uint64{math.MaxUint64, 1}); err != nil {
+		t.Errorf("NewIntScaled with a tiny weight failed: %v", err)
+	}
+
+	if _, err := NewIntScaled([]uint64{math.MaxUint64, 0}); err == nil {
+		t.Errorf("NewIntScaled with a zero weight did not fail")
+	}
+	if _, err := NewIntScaled(nil); err == nil {
+		t.Errorf("NewIntScaled with no weights did not fail")
+	}
+}
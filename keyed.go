@@ -0,0 +1,294 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Duplicates controls what NewKeyed does with a key that appears more than
+// once.
+type Duplicates int
+
+const (
+	// SumDuplicates adds together the weights of a repeated key.
+	SumDuplicates Duplicates = iota
+
+	// RejectDuplicates makes NewKeyed return an error for a repeated key.
+	RejectDuplicates
+)
+
+// Keyed picks string keys rather than indices.
+type Keyed struct {
+	al   *Alias
+	keys []string
+}
+
+// Create a new keyed alias object. keys and weights are parallel slices;
+// every weight must be positive. Keys keep the order of their first
+// appearance.
+func NewKeyed(keys []string, weights []float64, dups Duplicates) (*Keyed, error) {
+	if len(keys) != len(weights) {
+		return nil, errors.New("keys and weights have different lengths")
+	}
+
+	var k Keyed
+	var prob []float64
+	seen := make(map[string]int, len(keys))
+	for i, key := range keys {
+		if weights[i] <= 0 {
+			return nil, errors.New("a probability is non-positive")
+		}
+
+		if j, ok := seen[key]; ok {
+			if dups == RejectDuplicates {
+				return nil, errors.New("duplicate key " + key)
+			}
+			prob[j] += weights[i]
+			continue
+		}
+
+		seen[key] = len(k.keys)
+		k.keys = append(k.keys, key)
+		prob = append(prob, weights[i])
+	}
+
+	var err error
+	k.al, err = New(prob)
+	if err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+// Create a new keyed alias object from a map of keys to weights. The keys
+// are sorted, so the table doesn't depend on map iteration order.
+func NewKeyedMap(weights map[string]float64) (*Keyed, error) {
+	keys := make([]string, 0, len(weights))
+	for key := range weights {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ws := make([]float64, len(keys))
+	for i, key := range keys {
+		ws[i] = weights[key]
+	}
+
+	return NewKeyed(keys, ws, RejectDuplicates)
+}
+
+// Keys returns the distinct keys, in the order of their indices.
+func (k *Keyed) Keys() []string {
+	return append([]string(nil), k.keys...)
+}
+
+// Gen picks a random key according to the distribution using the rng passed.
+func (k *Keyed) Gen(rng *rand.Rand) string {
+	return k.keys[k.al.Gen(rng)]
+}
+
+// MarshalBinary implements encoding.BinaryMarshaller. The keys are stored
+// along with the table, so the unmarshalled object returns the same keys.
+//
+// The format is the number of keys as a little endian uint32, then each key
+// as a little endian uint32 length followed by its bytes, then the table as
+// written by Alias.MarshalBinary.
+func (k *Keyed) MarshalBinary() ([]byte, error) {
+	table, err := k.al.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	size := 4 + len(table)
+	for _, key := range k.keys {
+		size += 4 + len(key)
+	}
+
+	out := make([]byte, 4, size)
+	binary.LittleEndian.PutUint32(out, uint32(len(k.keys)))
+	for _, key := range k.keys {
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(key)))
+		out = append(out, key...)
+	}
+	out = append(out, table...)
+
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaller.
+func (k *Keyed) UnmarshalBinary(p []byte) error {
+	if len(p) < 4 {
+		return errors.New("bad data length")
+	}
+	n := binary.LittleEndian.Uint32(p)
+	p = p[4:]
+
+	// every key needs at least its length prefix
+	if uint64(n)*4 > uint64(len(p)) {
+		return errors.New("bad data: too many keys")
+	}
+
+	keys := make([]string, n)
+	for i := range keys {
+		if len(p) < 4 {
+			return errors.New("bad data length")
+		}
+		l := binary.LittleEndian.Uint32(p)
+		p = p[4:]
+
+		if uint64(l) > uint64(len(p)) {
+			return errors.New("bad data length")
+		}
+		keys[i] = string(p[:l])
+		p = p[l:]
+	}
+
+	al := &Alias{}
+	if err := al.UnmarshalBinary(p); err != nil {
+		return err
+	}
+	if len(al.table) != len(keys) {
+		return errors.New("bad data: key count does not match table")
+	}
+
+	k.al = al
+	k.keys = keys
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, for storing keyed tables
+// in version control. Each key gets a line with the key, quoted as a Go
+// string, a space, and its probability as encoded in the table. Lines are
+// sorted by key, so the output depends only on the keys and their
+// probabilities, and changing one key's weight changes few lines.
+func (k *Keyed) MarshalText() ([]byte, error) {
+	order := make([]int, len(k.keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return k.keys[order[a]] < k.keys[order[b]]
+	})
+
+	var buf bytes.Buffer
+	for _, i := range order {
+		buf.WriteString(strconv.Quote(k.keys[i]))
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatFloat(k.al.Prob(uint32(i)), 'g', -1, 64))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Keys may appear in any
+// order, but only once each; the unmarshalled object's indices follow the
+// sorted order of the keys. Blank lines are ignored.
+//
+// The probabilities written by MarshalText are whole numbers of 2^-31ths,
+// and the table is rebuilt to give exactly those, so text passed through
+// UnmarshalText and MarshalText comes back unchanged. Other probabilities,
+// such as edited by hand, are normalized and rounded to the nearest such
+// numbers first.
+func (k *Keyed) UnmarshalText(p []byte) error {
+	weights := make(map[string]float64)
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		line := strings.TrimSpace(string(line))
+		if line == "" {
+			continue
+		}
+
+		sp := strings.LastIndexByte(line, ' ')
+		if sp < 0 {
+			return errors.New("bad data: missing probability")
+		}
+		key, err := strconv.Unquote(line[:sp])
+		if err != nil {
+			return errors.New("bad data: badly quoted key")
+		}
+		w, err := strconv.ParseFloat(line[sp+1:], 64)
+		if err != nil {
+			return errors.New("bad data: bad probability")
+		}
+
+		if _, ok := weights[key]; ok {
+			return errors.New("bad data: duplicate key " + key)
+		}
+		weights[key] = w
+	}
+
+	keys := make([]string, 0, len(weights))
+	for key := range weights {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	prob := make([]float64, len(keys))
+	for i, key := range keys {
+		prob[i] = weights[key]
+	}
+
+	// keys whose probability rounds to zero stay, with zero
+	counts, err := roundCounts(prob)
+	if err != nil {
+		return errors.New("bad data: " + err.Error())
+	}
+
+	k.al = fromCounts(counts)
+	k.keys = keys
+	return nil
+}
+
+// roundCounts normalizes prob and rounds it to whole numbers of 2^-31ths,
+// returned as counts summing to 2^31 for fromCounts. Counts are rounded
+// down, and the values left over go one each to the items with the largest
+// remainders, so probabilities that are already whole numbers of 2^-31ths
+// come through unchanged.
+func roundCounts(prob []float64) ([]uint64, error) {
+	total, err := checkProb(prob, true)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]uint64, len(prob))
+	rem := make([]float64, len(prob))
+	left := int64(1 << 31)
+	for i, p := range prob {
+		x := p / total * (1 << 31)
+		counts[i] = uint64(x)
+		rem[i] = x - float64(counts[i])
+		left -= int64(counts[i])
+	}
+
+	order := make([]int, len(prob))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rem[order[a]] > rem[order[b]]
+	})
+
+	// floating point error can leave the counts a little over as well as
+	// under; take the excess from the smallest remainders
+	for j := 0; left > 0; j++ {
+		counts[order[j%len(order)]]++
+		left--
+	}
+	for j := 0; left < 0; j++ {
+		if i := order[len(order)-1-j%len(order)]; counts[i] > 0 {
+			counts[i]--
+			left++
+		}
+	}
+
+	return counts, nil
+}
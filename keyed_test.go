@@ -0,0 +1,184 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeyed(t *testing.T) {
+	keys := []string{"a", "b", "a", "c"}
+	weights := []float64{1, 2, 3, 4}
+
+	k, err := NewKeyed(keys, weights, SumDuplicates)
+	if err != nil {
+		t.Fatalf("Couldn't create keyed alias: %v", err)
+	}
+
+	if got := k.Keys(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("Keys was %v", got)
+	}
+
+	index := map[string]uint32{"a": 0, "b": 1, "c": 2}
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		return index[k.Gen(rng)]
+	}, []float64{4, 2, 4}, 1)
+
+	if _, err := NewKeyed(keys, weights, RejectDuplicates); err == nil {
+		t.Errorf("NewKeyed with RejectDuplicates did not fail on a repeated key")
+	}
+}
+
+func TestKeyedMarshalBinary(t *testing.T) {
+	k, err := NewKeyed([]string{"x", "", "long key"}, []float64{1, 2, 3}, RejectDuplicates)
+	if err != nil {
+		t.Fatalf("Couldn't create keyed alias: %v", err)
+	}
+
+	data, err := k.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Couldn't MarshalBinary: %v", err)
+	}
+
+	k2 := &Keyed{}
+	if err := k2.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Couldn't UnmarshalBinary: %v", err)
+	}
+
+	if !reflect.DeepEqual(k, k2) {
+		t.Fatalf("Unmarshalled version was not the same as original")
+	}
+
+	for i := 0; i < len(data); i++ {
+		if err := k2.UnmarshalBinary(data[:i]); err == nil {
+			t.Errorf("UnmarshalBinary of %v truncated bytes did not fail", len(data)-i)
+		}
+	}
+}
+
+func TestNewKeyedMap(t *testing.T) {
+	k, err := NewKeyedMap(map[string]float64{"c": 3, "a": 1, "b": 2})
+	if err != nil {
+		t.Fatalf("Couldn't create keyed alias: %v", err)
+	}
+	if got := k.Keys(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("Keys was %v", got)
+	}
+	checkDistribution(t, k.al.Gen, []float64{1, 2, 3}, 1)
+}
+
+func TestKeyedMarshalText(t *testing.T) {
+	k, err := NewKeyed([]string{"x", "", "long \"key\"\n"}, []float64{1, 2, 1}, RejectDuplicates)
+	if err != nil {
+		t.Fatalf("Couldn't create keyed alias: %v", err)
+	}
+
+	text, err := k.MarshalText()
+	if err != nil {
+		t.Fatalf("Couldn't MarshalText: %v", err)
+	}
+
+	want := "\"\" 0.5\n\"long \\\"key\\\"\\n\" 0.25\n\"x\" 0.25\n"
+	if string(text) != want {
+		t.Errorf("MarshalText gave %q, wanted %q", text, want)
+	}
+
+	// the same keys and weights in another order give the same text
+	k2, err := NewKeyed([]string{"long \"key\"\n", "x", ""}, []float64{1, 1, 2}, RejectDuplicates)
+	if err != nil {
+		t.Fatalf("Couldn't create keyed alias: %v", err)
+	}
+	text2, _ := k2.MarshalText()
+	if string(text2) != string(text) {
+		t.Errorf("MarshalText depends on key order: %q vs %q", text2, text)
+	}
+
+	k3 := &Keyed{}
+	if err := k3.UnmarshalText(text); err != nil {
+		t.Fatalf("Couldn't UnmarshalText: %v", err)
+	}
+	if got := k3.Keys(); !reflect.DeepEqual(got, []string{"", "long \"key\"\n", "x"}) {
+		t.Errorf("Keys was %q", got)
+	}
+	checkDistribution(t, k3.al.Gen, []float64{2, 1, 1}, 1)
+
+	for _, bad := range []string{
+		"\"a\"\n",
+		"a 1\n",
+		"\"a\" x\n",
+		"\"a\" 1\n\"a\" 1\n",
+		"\"a\" 0\n",
+	} {
+		if err := k3.UnmarshalText([]byte(bad)); err == nil {
+			t.Errorf("UnmarshalText(%q) did not fail", bad)
+		}
+	}
+}
+
+func TestKeyedMarshalTextRoundTrip(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e", "f", "g"}
+	tables := [][]float64{
+		{1, 2, 3, 4, 5, 6, 7},
+		{0.1, 1e-7, 3, 1e-9, 0.7, 2.5, 1},
+		{1000, 1, 1, 1, 1, 1, 1},
+		{math.Pi, math.E, math.Sqrt2, 1, 1.5, 0.3, 0.01},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		weights := make([]float64, len(keys))
+		for j := range weights {
+			weights[j] = rng.ExpFloat64()
+		}
+		tables = append(tables, weights)
+	}
+	for _, weights := range tables {
+		k, err := NewKeyed(keys, weights, RejectDuplicates)
+		if err != nil {
+			t.Fatalf("Couldn't create keyed alias: %v", err)
+		}
+		text, _ := k.MarshalText()
+
+		var k2 Keyed
+		if err := k2.UnmarshalText(text); err != nil {
+			t.Fatalf("Couldn't UnmarshalText: %v", err)
+		}
+		if text2, _ := k2.MarshalText(); string(text2) != string(text) {
+			t.Errorf("MarshalText after UnmarshalText gave %q, wanted %q", text2, text)
+		}
+	}
+
+	// hand-written probabilities are rounded once, and then stay put
+	var k Keyed
+	if err := k.UnmarshalText([]byte("\"a\" 1\n\"b\" 1\n\"c\" 1\n")); err != nil {
+		t.Fatalf("Couldn't UnmarshalText: %v", err)
+	}
+	text, _ := k.MarshalText()
+	var k2 Keyed
+	if err := k2.UnmarshalText(text); err != nil {
+		t.Fatalf("Couldn't UnmarshalText: %v", err)
+	}
+	if text2, _ := k2.MarshalText(); string(text2) != string(text) {
+		t.Errorf("MarshalText after UnmarshalText gave %q, wanted %q", text2, text)
+	}
+
+	// keys longer than bufio.Scanner's default limit
+	long := strings.Repeat("x", 100000)
+	k3, err := NewKeyed([]string{long, "y"}, []float64{1, 3}, RejectDuplicates)
+	if err != nil {
+		t.Fatalf("Couldn't create keyed alias: %v", err)
+	}
+	text, _ = k3.MarshalText()
+	var k4 Keyed
+	if err := k4.UnmarshalText(text); err != nil {
+		t.Fatalf("Couldn't UnmarshalText a long key: %v", err)
+	}
+	if got := k4.Keys(); !reflect.DeepEqual(got, []string{long, "y"}) {
+		t.Errorf("UnmarshalText of a long key gave %v keys", len(got))
+	}
+}
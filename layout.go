@@ -0,0 +1,98 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Layout describes a raw binary encoding of a table, so that consumers such
+// as C code on embedded targets can read a table in their native layout.
+//
+// Each slot is a uint32 threshold in [0,2^31) and a uint32 alias target. To
+// draw from the table, take a uniform r in [0,2^31); the slot is r%n, and the
+// result is the slot's own index if r <= threshold and the threshold is
+// nonzero, and its alias otherwise.
+// The slot and the comparison both come from the same r, so every draw
+// takes exactly one 31-bit random value and never a second; a 32- or 64-bit
+// hardware random word always suffices.
+//
+// The zero Layout, little endian and interleaved, is the v1 format once
+// written by MarshalBinary, and the body of the v2 format it writes now.
+type Layout struct {
+	// Order is the byte order of every field. nil means little endian.
+	Order binary.ByteOrder
+
+	// Split stores all the thresholds followed by all the alias targets,
+	// instead of each slot's threshold followed by its alias target.
+	Split bool
+}
+
+func (l Layout) order() binary.ByteOrder {
+	if l.Order == nil {
+		return binary.LittleEndian
+	}
+	return l.Order
+}
+
+// offsets returns where slot i's threshold and alias target are stored in a
+// table of n slots.
+func (l Layout) offsets(i, n int) (int, int) {
+	if l.Split {
+		return i * 4, (n + i) * 4
+	}
+	return i * 8, i*8 + 4
+}
+
+// MarshalLayout encodes the table in the given layout.
+func (al *Alias) MarshalLayout(l Layout) []byte {
+	order := l.order()
+	n := len(al.table)
+
+	out := make([]byte, n*8)
+	for i, piece := range al.table {
+		po, ao := l.offsets(i, n)
+		order.PutUint32(out[po:po+4], piece.prob)
+		order.PutUint32(out[ao:ao+4], piece.alias)
+	}
+	return out
+}
+
+// UnmarshalLayout decodes a table written in the given layout.
+func (al *Alias) UnmarshalLayout(p []byte, l Layout) error {
+	if len(p)%8 != 0 {
+		return errors.New("bad data length")
+	}
+
+	if int(uint32(len(p)/8)) != len(p)/8 {
+		return errors.New("data too large")
+	}
+
+	order := l.order()
+	n := len(p) / 8
+
+	table := make([]ipiece, n)
+	for i := range table {
+		po, ao := l.offsets(i, n)
+		prob := order.Uint32(p[po : po+4])
+		alias := order.Uint32(p[ao : ao+4])
+
+		if prob >= 1<<31 {
+			return errors.New("bad data: probability out of range")
+		}
+		if alias >= uint32(len(table)) {
+			return errors.New("bad data: alias target out of range")
+		}
+
+		table[i].prob = prob
+		table[i].alias = alias
+	}
+
+	// reset everything derived from the old table
+	*al = Alias{table: table}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	a, err := New([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 1000})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	def, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Couldn't MarshalBinary: %v", err)
+	}
+	if !bytes.Equal(def[formatHeader:len(def)-formatFooter], a.MarshalLayout(Layout{})) {
+		t.Errorf("The zero layout differs from the body of MarshalBinary")
+	}
+
+	layouts := []Layout{
+		{},
+		{Order: binary.BigEndian},
+		{Split: true},
+		{Order: binary.BigEndian, Split: true},
+	}
+	for _, l := range layouts {
+		data := a.MarshalLayout(l)
+
+		a2 := &Alias{}
+		if err := a2.UnmarshalLayout(data, l); err != nil {
+			t.Fatalf("Couldn't UnmarshalLayout(%+v): %v", l, err)
+		}
+		if !reflect.DeepEqual(a, a2) {
+			t.Errorf("Round trip through %+v changed the table", l)
+		}
+	}
+
+	split := a.MarshalLayout(Layout{Order: binary.BigEndian, Split: true})
+	for i, piece := range a.table {
+		if binary.BigEndian.Uint32(split[i*4:]) != piece.prob {
+			t.Errorf("Split layout has the wrong threshold in slot %v", i)
+		}
+		if binary.BigEndian.Uint32(split[(len(a.table)+i)*4:]) != piece.alias {
+			t.Errorf("Split layout has the wrong alias in slot %v", i)
+		}
+	}
+}
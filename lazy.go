@@ -0,0 +1,74 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// Lazy builds a table the first time it's needed, so a service holding many
+// rarely used distributions only pays for the ones it draws from. The build
+// runs once, even if many goroutines need the table at the same moment, and
+// its result, table or error, is kept.
+//
+// A Lazy is safe for concurrent use, provided each goroutine uses its own
+// rng.
+type Lazy struct {
+	get  func() (*Alias, error)
+	done atomic.Bool
+	err  error // set before done
+}
+
+// NewLazy returns a Lazy that builds its table with build.
+func NewLazy(build func() (*Alias, error)) *Lazy {
+	l := &Lazy{}
+	l.get = sync.OnceValues(func() (*Alias, error) {
+		al, err := build()
+		l.err = err
+		l.done.Store(true)
+		return al, err
+	})
+	return l
+}
+
+// NewLazyProb returns a Lazy that builds its table with New from the
+// probabilities prob returns.
+func NewLazyProb(prob func() []float64) *Lazy {
+	return NewLazy(func() (*Alias, error) {
+		return New(prob())
+	})
+}
+
+// Alias builds the table if it hasn't been built yet, and returns it.
+func (l *Lazy) Alias() (*Alias, error) {
+	return l.get()
+}
+
+// Gen draws from the table as Alias.Gen does, building it first if needed.
+// It returns the build's error if the table couldn't be built.
+func (l *Lazy) Gen(rng *rand.Rand) (uint32, error) {
+	al, err := l.get()
+	if err != nil {
+		return 0, err
+	}
+	return al.Gen(rng), nil
+}
+
+// Ready reports whether the build has finished, successfully or not. It
+// doesn't start the build.
+func (l *Lazy) Ready() bool {
+	return l.done.Load()
+}
+
+// Err returns the build's error, or nil if the build succeeded or hasn't
+// finished. It doesn't start the build.
+func (l *Lazy) Err() error {
+	if !l.done.Load() {
+		return nil
+	}
+	return l.err
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	builds := 0
+	l := NewLazyProb(func() []float64 {
+		builds++
+		return []float64{1, 3}
+	})
+	if l.Ready() || l.Err() != nil {
+		t.Fatalf("Lazy was ready before use")
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < 100; i++ {
+				if _, err := l.Gen(rng); err != nil {
+					t.Errorf("Gen failed: %v", err)
+					return
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	if builds != 1 {
+		t.Errorf("Table was built %v times, wanted 1", builds)
+	}
+	if !l.Ready() || l.Err() != nil {
+		t.Errorf("Lazy wasn't ready after use")
+	}
+
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		i, _ := l.Gen(rng)
+		return i
+	}, []float64{1, 3}, 1)
+}
+
+func TestLazyError(t *testing.T) {
+	l := NewLazyProb(func() []float64 { return nil })
+	if l.Err() != nil {
+		t.Errorf("Err reported an error before the build")
+	}
+	if _, err := l.Gen(rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("Gen of a failed build did not fail")
+	}
+	if !l.Ready() || l.Err() == nil {
+		t.Errorf("Err didn't report the failed build")
+	}
+}
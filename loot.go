@@ -0,0 +1,157 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+// LootEntry is one weighted entry in a loot table. It drops an item if Item
+// is set, rolls another table if Table is set, and drops nothing if neither
+// is set.
+type LootEntry struct {
+	Item   string
+	Table  string
+	Weight float64
+}
+
+// Loot is a set of compiled loot tables.
+//
+// Rolling a table that refers to other tables is the same as rolling a
+// single table over all the items they can drop, so each table is compiled
+// down to one alias table over its possible drops, and a roll costs one
+// draw however deeply the tables nest.
+type Loot struct {
+	tables map[string]*lootTable
+}
+
+type lootTable struct {
+	al    *Alias
+	items []string // the drop for each index; past the end means nothing
+}
+
+// CompileLoot compiles loot table definitions, keyed by table name. Weights
+// must be positive, every table must have at least one entry, and references
+// must name defined tables without forming a cycle.
+func CompileLoot(defs map[string][]LootEntry) (*Loot, error) {
+	c := lootCompiler{
+		defs:   defs,
+		dists:  make(map[string]lootDist),
+		active: make(map[string]bool),
+	}
+
+	l := &Loot{tables: make(map[string]*lootTable, len(defs))}
+	for name := range defs {
+		dist, err := c.resolve(name)
+		if err != nil {
+			return nil, err
+		}
+
+		var t lootTable
+		for item := range dist.items {
+			t.items = append(t.items, item)
+		}
+		sort.Strings(t.items)
+
+		prob := make([]float64, 0, len(t.items)+1)
+		for _, item := range t.items {
+			prob = append(prob, dist.items[item])
+		}
+		if dist.nothing > 0 {
+			prob = append(prob, dist.nothing)
+		}
+
+		t.al, err = New(prob)
+		if err != nil {
+			return nil, err
+		}
+		l.tables[name] = &t
+	}
+
+	return l, nil
+}
+
+// Roll rolls the named table, returning the item dropped, or false if the
+// roll dropped nothing or there's no such table.
+func (l *Loot) Roll(rng *rand.Rand, table string) (string, bool) {
+	t, ok := l.tables[table]
+	if !ok {
+		return "", false
+	}
+
+	i := t.al.Gen(rng)
+	if int(i) >= len(t.items) {
+		return "", false
+	}
+	return t.items[i], true
+}
+
+// lootDist is the flattened distribution of a loot table's drops.
+type lootDist struct {
+	items   map[string]float64
+	nothing float64
+}
+
+type lootCompiler struct {
+	defs   map[string][]LootEntry
+	dists  map[string]lootDist
+	active map[string]bool // tables being resolved, for cycle detection
+}
+
+func (c *lootCompiler) resolve(name string) (lootDist, error) {
+	if d, ok := c.dists[name]; ok {
+		return d, nil
+	}
+	if c.active[name] {
+		return lootDist{}, errors.New("loot table " + name + " refers to itself")
+	}
+
+	entries, ok := c.defs[name]
+	if !ok {
+		return lootDist{}, errors.New("no loot table named " + name)
+	}
+	if len(entries) == 0 {
+		return lootDist{}, errors.New("loot table " + name + " is empty")
+	}
+
+	c.active[name] = true
+	defer delete(c.active, name)
+
+	total := float64(0)
+	for _, e := range entries {
+		if e.Weight <= 0 {
+			return lootDist{}, errors.New("loot table " + name + " has a non-positive weight")
+		}
+		if e.Item != "" && e.Table != "" {
+			return lootDist{}, errors.New("loot table " + name + " has an entry with both an item and a table")
+		}
+		total += e.Weight
+	}
+
+	d := lootDist{items: make(map[string]float64)}
+	for _, e := range entries {
+		p := e.Weight / total
+		switch {
+		case e.Item != "":
+			d.items[e.Item] += p
+		case e.Table != "":
+			sub, err := c.resolve(e.Table)
+			if err != nil {
+				return lootDist{}, err
+			}
+			for item, q := range sub.items {
+				d.items[item] += p * q
+			}
+			d.nothing += p * sub.nothing
+		default:
+			d.nothing += p
+		}
+	}
+
+	c.dists[name] = d
+	return d, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLoot(t *testing.T) {
+	l, err := CompileLoot(map[string][]LootEntry{
+		"chest": {
+			{Item: "gold", Weight: 2},
+			{Table: "gems", Weight: 1},
+			{Weight: 1},
+		},
+		"gems": {
+			{Item: "ruby", Weight: 1},
+			{Item: "gold", Weight: 1},
+			{Weight: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Couldn't compile loot: %v", err)
+	}
+
+	// gold: 0.5 + 0.25*0.25, ruby: 0.25*0.25, nothing: 0.25 + 0.25*0.5
+	index := map[string]uint32{"gold": 0, "ruby": 1}
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		item, ok := l.Roll(rng, "chest")
+		if !ok {
+			return 2
+		}
+		return index[item]
+	}, []float64{9, 1, 6}, 1)
+
+	if _, ok := l.Roll(rand.New(rand.NewSource(1)), "barrel"); ok {
+		t.Errorf("Rolling a missing table dropped something")
+	}
+
+	bad := []map[string][]LootEntry{
+		{"a": {{Table: "b", Weight: 1}}, "b": {{Table: "a", Weight: 1}}},
+		{"a": {{Table: "a", Weight: 1}}},
+		{"a": {{Table: "missing", Weight: 1}}},
+		{"a": {}},
+		{"a": {{Item: "x", Weight: 0}}},
+		{"a": {{Item: "x", Table: "a", Weight: 1}}},
+	}
+	for _, defs := range bad {
+		if _, err := CompileLoot(defs); err == nil {
+			t.Errorf("CompileLoot(%v) did not fail", defs)
+		}
+	}
+}
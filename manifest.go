@@ -0,0 +1,85 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Manifest records what's needed to reproduce draws made with GenParallel,
+// so it can be stored alongside a generated dataset as its provenance and
+// later checked against a binary with Verify.
+type Manifest struct {
+	// Algorithm is the version of the method that turns random values into
+	// draws. It changes only if the same table and random values would give
+	// different draws.
+	Algorithm int `json:"algorithm"`
+
+	// Fingerprint is the table's Fingerprint.
+	Fingerprint string `json:"fingerprint"`
+
+	// RNG names the generator, and Seeds the way each worker's seed is
+	// derived from Seed.
+	RNG   string `json:"rng"`
+	Seeds string `json:"seeds"`
+	Seed  int64  `json:"seed"`
+
+	// Check is a hash of the first 1024 draws, which catches any
+	// change in behavior that the fields above don't describe.
+	Check string `json:"check"`
+}
+
+const (
+	manifestAlgorithm = 1
+	manifestRNG       = "math/rand.NewSource"
+	manifestSeeds     = "splitmix64"
+	manifestDraws     = 1024
+)
+
+// Manifest returns the manifest for draws made with GenParallel from a
+// SeedSequence with the given master seed.
+func (al *Alias) Manifest(seed int64) Manifest {
+	return Manifest{
+		Algorithm:   manifestAlgorithm,
+		Fingerprint: al.Fingerprint(),
+		RNG:         manifestRNG,
+		Seeds:       manifestSeeds,
+		Seed:        seed,
+		Check:       al.manifestCheck(seed),
+	}
+}
+
+// Verify checks that this binary and table reproduce the draws m describes,
+// and returns an error describing the first difference if not.
+func (al *Alias) Verify(m Manifest) error {
+	switch {
+	case m.Algorithm != manifestAlgorithm:
+		return errors.New("draws use algorithm version " + strconv.Itoa(manifestAlgorithm) + ", not " + strconv.Itoa(m.Algorithm))
+	case m.RNG != manifestRNG:
+		return errors.New("draws use generator " + manifestRNG + ", not " + m.RNG)
+	case m.Seeds != manifestSeeds:
+		return errors.New("seeds are derived by " + manifestSeeds + ", not " + m.Seeds)
+	case m.Fingerprint != al.Fingerprint():
+		return errors.New("table fingerprint " + al.Fingerprint() + " doesn't match " + m.Fingerprint)
+	case m.Check != al.manifestCheck(m.Seed):
+		return errors.New("draws don't match the manifest's check")
+	}
+	return nil
+}
+
+// manifestCheck hashes the first manifestDraws draws from seed.
+func (al *Alias) manifestCheck(seed int64) string {
+	out := make([]uint32, manifestDraws)
+	al.GenParallel(NewSeedSequence(seed), out, 1)
+
+	b := make([]byte, 4*len(out))
+	for i, v := range out {
+		binary.LittleEndian.PutUint32(b[4*i:], v)
+	}
+	return fmt.Sprintf("%016x", fnv1a(b))
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 3})
+	m := a.Manifest(42)
+
+	// survives a round trip through JSON
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Couldn't marshal manifest: %v", err)
+	}
+	var m2 Manifest
+	if err := json.Unmarshal(data, &m2); err != nil {
+		t.Fatalf("Couldn't unmarshal manifest: %v", err)
+	}
+	if err := a.Verify(m2); err != nil {
+		t.Errorf("Verify failed on the table's own manifest: %v", err)
+	}
+
+	if err := mustNew(t, []float64{1, 2, 4}).Verify(m); err == nil {
+		t.Errorf("Verify with a different table did not fail")
+	}
+
+	bad := m
+	bad.Algorithm++
+	if err := a.Verify(bad); err == nil {
+		t.Errorf("Verify with a different algorithm did not fail")
+	}
+
+	bad = m
+	bad.Check = a.Manifest(43).Check
+	if err := a.Verify(bad); err == nil {
+		t.Errorf("Verify with a different check did not fail")
+	}
+}
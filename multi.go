@@ -0,0 +1,66 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "math/rand"
+
+// MultiGen draws one random number from each table, storing the draw from
+// tables[i] in out[i]. out must be at least as long as tables.
+//
+// Every call to rng.Int63 supplies two draws, so MultiGen uses half as many
+// random words as calling Gen on each table, and avoids a call per table.
+// The results are not the same as those of calling Gen in turn.
+func MultiGen(rng *rand.Rand, tables []*Alias, out []uint32) {
+	out = out[:len(tables)]
+
+	i := 0
+	for ; i+1 < len(tables); i += 2 {
+		x := uint64(rng.Int63())
+		out[i] = tables[i].genFrom(uint32(x) & (1<<31 - 1))
+		out[i+1] = tables[i+1].genFrom(uint32(x>>31) & (1<<31 - 1))
+	}
+	if i < len(tables) {
+		out[i] = tables[i].Gen(rng)
+	}
+}
+
+// GenN fills dst with draws from the table. Like MultiGen, every call to
+// rng.Int63 supplies two draws, so filling a large buffer takes half the
+// random words and far fewer calls than calling Gen for each element. The
+// results are not the same as those of calling Gen in turn.
+func (al *Alias) GenN(rng *rand.Rand, dst []uint32) {
+	i := 0
+	for ; i+1 < len(dst); i += 2 {
+		x := uint64(rng.Int63())
+		dst[i] = al.genFrom(uint32(x) & (1<<31 - 1))
+		dst[i+1] = al.genFrom(uint32(x>>31) & (1<<31 - 1))
+	}
+	if i < len(dst) {
+		dst[i] = al.Gen(rng)
+	}
+}
+
+// GenFromWords fills dst with draws made from the uniformly random words in
+// words, rather than from an RNG, so randomness can come from elsewhere (a
+// vectorized generator, a GPU, a hardware source). Each word supplies two
+// draws, one from its low 31 bits and one from bits 32 through 62.
+//
+// It returns the number of draws written, which is the smaller of len(dst)
+// and 2*len(words).
+func (al *Alias) GenFromWords(words []uint64, dst []uint32) int {
+	if len(dst) > 2*len(words) {
+		dst = dst[:2*len(words)]
+	}
+
+	for i := range dst {
+		w := words[i/2]
+		if i%2 == 1 {
+			w >>= 32
+		}
+		dst[i] = al.genFrom(uint32(w) & (1<<31 - 1))
+	}
+
+	return len(dst)
+}
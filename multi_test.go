@@ -0,0 +1,81 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMultiGen(t *testing.T) {
+	dists := [][]float64{
+		{1, 2, 3},
+		{9, 8, 1, 4, 2},
+		{1000, 1, 3, 10},
+	}
+	tables := make([]*Alias, len(dists))
+	for i, dist := range dists {
+		var err error
+		tables[i], err = New(dist)
+		if err != nil {
+			t.Fatalf("Couldn't create alias: %v", err)
+		}
+	}
+
+	// draw every table on each call, but check one at a time
+	out := make([]uint32, len(tables))
+	for i, dist := range dists {
+		checkDistribution(t, func(rng *rand.Rand) uint32 {
+			MultiGen(rng, tables, out)
+			return out[i]
+		}, dist, int64(i))
+	}
+}
+
+func TestGenN(t *testing.T) {
+	dist := []float64{9, 8, 1, 4, 2}
+	a := mustNew(t, dist)
+
+	// an odd length, so the last draw of each batch comes from Gen
+	dst := make([]uint32, 999)
+	pos := len(dst)
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		if pos == len(dst) {
+			a.GenN(rng, dst)
+			pos = 0
+		}
+		pos++
+		return dst[pos-1]
+	}, dist, 1)
+}
+
+func TestGenFromWords(t *testing.T) {
+	dist := []float64{9, 8, 1, 4, 2}
+	a, err := New(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	words := make([]uint64, 500)
+	dst := make([]uint32, 1000)
+	pos := len(dst)
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		if pos == len(dst) {
+			for i := range words {
+				words[i] = rng.Uint64()
+			}
+			if n := a.GenFromWords(words, dst); n != len(dst) {
+				t.Fatalf("GenFromWords wrote %v draws, wanted %v", n, len(dst))
+			}
+			pos = 0
+		}
+		pos++
+		return dst[pos-1]
+	}, dist, 1)
+
+	if n := a.GenFromWords(words[:3], dst); n != 6 {
+		t.Errorf("GenFromWords with 3 words wrote %v draws", n)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+)
+
+// binomialDirect is the largest number of trials binomial simulates one at
+// a time.
+const binomialDirect = 32
+
+// DrawCounts returns how many of n draws from the table land on each index,
+// without making the draws: the counts have the same multinomial
+// distribution as counting the results of n calls to Gen. Index by index,
+// the count is drawn from the binomial distribution of the draws left over
+// the index's share of the mass left, so the cost grows with the number of
+// indices and only logarithmically with n, making n in the billions cheap.
+func (al *Alias) DrawCounts(rng *rand.Rand, n uint64) []uint64 {
+	var counts []uint64
+	withLabel(al.profileLabel, "DrawCounts", func(context.Context) {
+		counts = al.drawCounts(rng, n)
+	})
+	return counts
+}
+
+// drawCounts does the work of DrawCounts.
+func (al *Alias) drawCounts(rng *rand.Rand, n uint64) []uint64 {
+	pmf := al.pmf()
+	counts := make([]uint64, len(pmf))
+
+	// The probabilities are whole numbers of 2^-31ths, so mass is exact.
+	mass := float64(1)
+	for i, p := range pmf {
+		if n == 0 {
+			break
+		}
+		if p <= 0 {
+			continue
+		}
+		if p >= mass {
+			counts[i] = n
+			break
+		}
+		c := binomial(rng, n, p/mass)
+		counts[i] = c
+		n -= c
+		mass -= p
+	}
+
+	return counts
+}
+
+// DrawCounts returns how many of n draws from the table land on each key, as
+// Alias.DrawCounts does, leaving out keys that get none.
+func (k *Keyed) DrawCounts(rng *rand.Rand, n uint64) map[string]uint64 {
+	out := make(map[string]uint64)
+	for i, c := range k.al.DrawCounts(rng, n) {
+		if c > 0 {
+			out[k.keys[i]] = c
+		}
+	}
+	return out
+}
+
+// binomial returns the number of successes in n independent trials that
+// each succeed with probability p.
+//
+// For large n it uses the order statistics of the n uniform values that
+// would decide the trials, as in Devroye's Non-Uniform Random Variate
+// Generation: the middle one has a beta distribution, drawn from two gamma
+// variates, and deciding which side of p it falls on settles half of the
+// trials at once, leaving a binomial problem of half the size.
+func binomial(rng *rand.Rand, n uint64, p float64) uint64 {
+	k := uint64(0)
+	for n > binomialDirect {
+		if p <= 0 {
+			return k
+		}
+		if p >= 1 {
+			return k + n
+		}
+
+		i := n/2 + 1
+		a := gammaVariate(rng, float64(i))
+		b := gammaVariate(rng, float64(n+1-i))
+		x := a / (a + b)
+
+		if x < p {
+			// the i smallest values are successes; the rest are uniform
+			// over (x,1)
+			k += i
+			n -= i
+			p = (p - x) / (1 - x)
+		} else {
+			// the i largest values are failures; the rest are uniform
+			// over (0,x)
+			n = i - 1
+			p = p / x
+		}
+	}
+
+	for ; n > 0; n-- {
+		if rng.Float64() < p {
+			k++
+		}
+	}
+	return k
+}
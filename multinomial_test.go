@@ -0,0 +1,89 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDrawCounts(t *testing.T) {
+	dist := []float64{2, 0, 5, 1, 2}
+	a, err := fromPMF(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	for _, n := range []uint64{0, 1, 10, 1000, 1e6, 3e9} {
+		counts := a.DrawCounts(rng, n)
+		total := uint64(0)
+		for _, c := range counts {
+			total += c
+		}
+		if total != n {
+			t.Errorf("Counts %v for %v draws add up to %v", counts, n, total)
+		}
+		if counts[1] != 0 {
+			t.Errorf("Index with zero probability got %v of %v draws", counts[1], n)
+		}
+
+		if n < 1e6 {
+			continue
+		}
+		for i, c := range counts {
+			p := a.Prob(uint32(i))
+			sd := math.Sqrt(float64(n) * p * (1 - p))
+			if math.Abs(float64(c)-float64(n)*p) > 5*sd+1 {
+				t.Errorf("Index %v got %v of %v draws, wanted about %v", i, c, n, float64(n)*p)
+			}
+		}
+	}
+}
+
+func TestKeyedDrawCounts(t *testing.T) {
+	k, err := NewKeyedMap(map[string]float64{"train": 8, "test": 1, "validate": 1})
+	if err != nil {
+		t.Fatalf("Couldn't create keyed: %v", err)
+	}
+
+	counts := k.DrawCounts(rand.New(rand.NewSource(1)), 5e9)
+	if len(counts) != 3 || counts["train"]+counts["test"]+counts["validate"] != 5e9 {
+		t.Errorf("Counts %v don't add up to 5e9", counts)
+	}
+	if math.Abs(float64(counts["train"])-4e9) > 1e6 {
+		t.Errorf("Got %v training examples, wanted about 4e9", counts["train"])
+	}
+}
+
+func TestBinomial(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	// the mean and variance over many draws
+	const draws = 20000
+	for _, tc := range []struct {
+		n uint64
+		p float64
+	}{{10, 0.3}, {1000, 0.01}, {100000, 0.5}, {1 << 40, 1e-9}} {
+		sum, sumSq := float64(0), float64(0)
+		for i := 0; i < draws; i++ {
+			k := float64(binomial(rng, tc.n, tc.p))
+			sum += k
+			sumSq += k * k
+		}
+		mean := sum / draws
+		variance := sumSq/draws - mean*mean
+
+		wantMean := float64(tc.n) * tc.p
+		wantVar := wantMean * (1 - tc.p)
+		if math.Abs(mean-wantMean) > 5*math.Sqrt(wantVar/draws) {
+			t.Errorf("binomial(%v, %v) had mean %v, wanted %v", tc.n, tc.p, mean, wantMean)
+		}
+		if math.Abs(variance-wantVar) > 0.05*wantVar {
+			t.Errorf("binomial(%v, %v) had variance %v, wanted %v", tc.n, tc.p, variance, wantVar)
+		}
+	}
+}
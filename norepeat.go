@@ -0,0 +1,57 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// GenNoRepeat generates a random number other than prev, according to the
+// distribution with prev removed and the rest renormalized. Passing the
+// previous result each time gives a sequence that never repeats an index
+// twice in a row, as for playlists, without the bias of simply drawing
+// again on a repeat. If prev is out of range, as for the first draw, it's
+// the same as Gen. It returns false if no other index has probability mass.
+//
+// When prev holds at most half the mass, it's drawn by rejection from Gen.
+// Otherwise it's drawn by binary search over the cumulative probabilities,
+// which are computed in O(n) on first use and cached.
+func (al *Alias) GenNoRepeat(rng *rand.Rand, prev uint32) (uint32, bool) {
+	pmf := al.pmf()
+	if int(prev) >= len(pmf) {
+		return al.Gen(rng), true
+	}
+
+	p := pmf[prev]
+	if p >= 1 {
+		return 0, false
+	}
+
+	if p <= 0.5 {
+		for {
+			i := al.Gen(rng)
+			if i != prev {
+				return i, true
+			}
+		}
+	}
+
+	// draw from the mass outside prev, skipping over prev's span of the cdf
+	cdf := al.cdf()
+	for {
+		u := rng.Float64() * (1 - p)
+		if u >= cdf[prev]-p {
+			u += p
+		}
+		i := sort.Search(len(cdf)-1, func(j int) bool {
+			return cdf[j] > u
+		})
+		// rounding can land u on prev's span's edges
+		if uint32(i) != prev {
+			return uint32(i), true
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGenNoRepeat(t *testing.T) {
+	a := mustNew(t, []float64{10, 1, 2, 3})
+
+	genAfter := func(prev uint32) func(*rand.Rand) uint32 {
+		return func(rng *rand.Rand) uint32 {
+			i, ok := a.GenNoRepeat(rng, prev)
+			if !ok {
+				t.Fatalf("GenNoRepeat(%v) failed", prev)
+			}
+			return i
+		}
+	}
+
+	// heavy prev, by binary search
+	checkDistribution(t, genAfter(0), []float64{0, 1, 2, 3}, 1)
+	// light prev, by rejection
+	checkDistribution(t, genAfter(2), []float64{10, 1, 0, 3}, 2)
+	// no prev
+	checkDistribution(t, genAfter(math.MaxUint32), []float64{10, 1, 2, 3}, 3)
+
+	rng := rand.New(rand.NewSource(1))
+	prev := uint32(math.MaxUint32)
+	for j := 0; j < 10000; j++ {
+		i, _ := a.GenNoRepeat(rng, prev)
+		if i == prev {
+			t.Fatalf("GenNoRepeat repeated %v", i)
+		}
+		prev = i
+	}
+
+	single := mustNew(t, []float64{1})
+	if i, ok := single.GenNoRepeat(rng, 0); ok {
+		t.Errorf("GenNoRepeat returned %v with no other index", i)
+	}
+}
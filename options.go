@@ -0,0 +1,297 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"unsafe"
+)
+
+// Options changes how NewWithOptions builds a table. The zero value builds
+// the same table as New.
+type Options struct {
+	// MaxBytes, if positive, caps the heap memory allocated while building
+	// the table, including the table itself. If the usual build would need
+	// more, the table is built in place instead, with no scratch memory
+	// beyond the table, which gives a different but equally accurate
+	// table. If even that would need more, NewWithOptions fails before
+	// allocating anything, and NewSampler falls back to a compact table.
+	//
+	// MinProb, MaxProb, Tolerance and Report each need some memory of their
+	// own per item, which counts against the cap.
+	MaxBytes int64
+
+	// Report, if not nil, is filled in with statistics about the table once
+	// it's built.
+	Report *BuildReport
+
+	// MinProb, if positive, is a floor on every item's probability. Items
+	// below it are raised to it, and the other items are scaled down to make
+	// room, keeping their ratios. MinProb times the number of items must not
+	// exceed 1.
+	MinProb float64
+
+	// MaxProb, if positive, is a cap on every item's probability. Mass above
+	// the cap is given to the other items in proportion to their weights.
+	// MaxProb times the number of items must be at least 1.
+	//
+	// MinProb and MaxProb may be used together, in which case every item's
+	// probability is its weight times a common scale, clamped to the bounds.
+	MaxProb float64
+
+	// Tolerance, if positive, is the largest acceptable difference between
+	// an item's requested probability and the probability the table
+	// actually gives it after quantization. If any item is off by more,
+	// NewWithOptions fails, and NewSampler escalates to a Wide table.
+	Tolerance float64
+
+	// ProfileLabel, if not empty, is attached as a pprof label while the
+	// table is built, and later while the table does long-running batch work
+	// (but not single draws, which are too short to be worth labeling). The
+	// labels are "alias", set to ProfileLabel, and "alias.op", naming the
+	// operation, so CPU profiles can attribute time to specific tables.
+	// The labeled work runs on its own goroutine, so the caller's labels
+	// are left as they were, but aren't seen by the labeled work.
+	ProfileLabel string
+}
+
+// BuildReport describes a newly built table and the distribution it was
+// built from, after any adjustment by the options, so suspicious
+// distributions can be caught at build time.
+type BuildReport struct {
+	N           int     // number of items
+	TotalWeight float64 // sum of the weights given
+	MinProb     float64 // smallest normalized probability
+	MaxProb     float64 // largest normalized probability
+	Entropy     float64 // Shannon entropy of the distribution, in bits
+	FullSlots   int     // slots that never return their alias
+
+	// Leftover is the normalized probability mass that floating point error
+	// left short in slots that should have been full, and which those slots'
+	// own items absorbed. It should be tiny.
+	Leftover float64
+
+	// MaxError is the largest difference between an item's requested
+	// probability and the probability the table gives it.
+	MaxError float64
+
+	// Compact is set by NewSampler if it built a compact Alias table
+	// rather than a Wide one, because the compact table was within
+	// Tolerance or to stay within MaxBytes. MaxError then shows the
+	// accuracy given up.
+	Compact bool
+}
+
+// Sampler is a table that can be drawn from: an *Alias or a *Wide.
+type Sampler interface {
+	Gen(rng *rand.Rand) uint32
+	Prob(i uint32) float64
+	Len() int
+}
+
+// sampler is what newWithOptions needs of a table.
+type sampler interface {
+	Sampler
+	pmf() []float64
+	fullSlots() int
+}
+
+// Create a new alias object, as with New, but with optional behavior.
+func NewWithOptions(prob []float64, opts Options) (*Alias, error) {
+	s, err := newWithOptions(prob, opts, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.(*Alias), nil
+}
+
+// NewSampler builds the most precise table that can be built within
+// opts.MaxBytes: a Wide table if it fits, or if MaxBytes is not set, and
+// otherwise an Alias table, which takes half the memory. Rather than failing
+// when the full-precision table is too big, it degrades to the compact one
+// and, if opts.Report is set, says so in the report along with the accuracy
+// lost. It only fails for MaxBytes if even the compact table doesn't fit.
+//
+// If opts.Tolerance is set, precise enough will do: it builds the compact
+// table first, and only escalates to a Wide table if the compact one's
+// quantization error exceeds Tolerance and the Wide one fits.
+//
+// The other options apply to either kind of table as they do for
+// NewWithOptions.
+func NewSampler(prob []float64, opts Options) (Sampler, error) {
+	wideFits := opts.MaxBytes <= 0 || optionsBytes(len(prob), opts, true, true) <= opts.MaxBytes
+
+	if opts.Tolerance > 0 || !wideFits {
+		s, err := newWithOptions(prob, opts, false)
+		if err != errTolerance || !wideFits {
+			if err != nil {
+				return nil, err
+			}
+			if opts.Report != nil {
+				opts.Report.Compact = true
+			}
+			return s, nil
+		}
+	}
+
+	return newWithOptions(prob, opts, true)
+}
+
+// errTolerance is returned by newWithOptions when the table it built
+// exceeds Tolerance.
+var errTolerance = errors.New("quantization error exceeds Tolerance")
+
+// optionsOverhead bounds the memory building any table allocates apart
+// from its per-item arrays: the table's header, closures and the like.
+const optionsOverhead = 4096
+
+// arraySlack bounds how much more than its size the runtime may allocate
+// for a large array, which it rounds up to whole pages.
+const arraySlack = 8192
+
+// optionsBytes returns the most heap memory building a table of n items
+// with opts allocates, in place if inPlace is set.
+func optionsBytes(n int, opts Options, wide, inPlace bool) int64 {
+	need := int64(optionsOverhead)
+	array := func(size uintptr) {
+		need += int64(n)*int64(size) + arraySlack
+	}
+
+	if wide {
+		array(unsafe.Sizeof(wpiece{}))
+	} else {
+		array(unsafe.Sizeof(ipiece{}))
+	}
+	if !inPlace {
+		// vose's stacks
+		array(unsafe.Sizeof(fpiece{}))
+	}
+	if opts.MinProb > 0 || opts.MaxProb > 0 {
+		// boundProbs' breakpoints and the adjusted copy of prob
+		array(16)
+		array(8)
+	}
+	if opts.Tolerance > 0 || opts.Report != nil {
+		// the table's probabilities, and the counts they're made from
+		array(8)
+		array(8)
+	}
+	return need
+}
+
+func newWithOptions(prob []float64, opts Options, wide bool) (sampler, error) {
+	pair := pairFunc(vose)
+	if opts.MaxBytes > 0 && optionsBytes(len(prob), opts, wide, false) > opts.MaxBytes {
+		if optionsBytes(len(prob), opts, wide, true) > opts.MaxBytes {
+			return nil, errors.New("table can't be built within MaxBytes")
+		}
+		pair = sweep
+	}
+
+	total := float64(0)
+	for _, v := range prob {
+		total += v
+	}
+
+	if opts.MinProb > 0 || opts.MaxProb > 0 {
+		var err error
+		prob, err = boundProbs(prob, opts.MinProb, opts.MaxProb)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var s sampler
+	var leftover float64
+	var err error
+	withLabel(opts.ProfileLabel, "build", func(context.Context) {
+		if wide {
+			var wd *Wide
+			wd, leftover, err = buildWide(prob, pair)
+			if err == nil {
+				wd.profileLabel = opts.ProfileLabel
+			}
+			s = wd
+		} else {
+			var al *Alias
+			al, leftover, err = buildWith(prob, false, pair)
+			if err == nil {
+				al.profileLabel = opts.ProfileLabel
+			}
+			s = al
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maxError := float64(0)
+	if opts.Tolerance > 0 || opts.Report != nil {
+		maxError = quantizationError(prob, s.pmf())
+	}
+	if opts.Tolerance > 0 && maxError > opts.Tolerance {
+		return nil, errTolerance
+	}
+
+	if opts.Report != nil {
+		*opts.Report = report(prob, total, leftover)
+		opts.Report.FullSlots = s.fullSlots()
+		opts.Report.MaxError = maxError
+	}
+
+	return s, nil
+}
+
+// quantizationError returns the largest difference between the normalized
+// probabilities in prob and those in pmf.
+func quantizationError(prob []float64, pmf []float64) float64 {
+	total := float64(0)
+	for _, v := range prob {
+		total += v
+	}
+
+	worst := float64(0)
+	for i, p := range pmf {
+		worst = math.Max(worst, math.Abs(p-prob[i]/total))
+	}
+	return worst
+}
+
+func report(prob []float64, total float64, leftover float64) BuildReport {
+	r := BuildReport{
+		N:           len(prob),
+		TotalWeight: total,
+		MinProb:     math.Inf(1),
+		Leftover:    leftover,
+	}
+
+	norm := float64(0)
+	for _, w := range prob {
+		norm += w
+	}
+
+	for _, w := range prob {
+		p := w / norm
+		r.MinProb = math.Min(r.MinProb, p)
+		r.MaxProb = math.Max(r.MaxProb, p)
+		r.Entropy -= p * math.Log2(p)
+	}
+
+	return r
+}
+
+// fullSlots returns the number of slots that never return their alias.
+func (al *Alias) fullSlots() int {
+	full := 0
+	for _, piece := range al.table {
+		if piece.prob == 1<<31-1 {
+			full++
+		}
+	}
+	return full
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"runtime"
+	"testing"
+)
+
+func TestMaxBytes(t *testing.T) {
+	prob := make([]float64, 1000)
+	for i := range prob {
+		prob[i] = float64(i + 1)
+	}
+
+	if _, err := NewWithOptions(prob, Options{MaxBytes: 1000}); err == nil {
+		t.Errorf("NewWithOptions with a tiny MaxBytes did not fail")
+	}
+
+	a, err := NewWithOptions(prob, Options{MaxBytes: 1 << 20})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	if len(a.table) != len(prob) {
+		t.Errorf("Table has %v entries, wanted %v", len(a.table), len(prob))
+	}
+}
+
+func TestMaxBytesInPlace(t *testing.T) {
+	prob := make([]float64, 100000)
+	for i := range prob {
+		prob[i] = float64(i%17 + 1)
+	}
+	want, err := New(prob)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	// room for the table alone
+	opts := Options{MaxBytes: optionsBytes(len(prob), Options{}, false, true)}
+	if opts.MaxBytes >= optionsBytes(len(prob), Options{}, false, false) {
+		t.Fatalf("MaxBytes %v leaves room for the usual build", opts.MaxBytes)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	a, err := NewWithOptions(prob, opts)
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatalf("Couldn't create alias within MaxBytes: %v", err)
+	}
+	if used := int64(after.TotalAlloc - before.TotalAlloc); used > opts.MaxBytes {
+		t.Errorf("Building allocated %v bytes, over MaxBytes %v", used, opts.MaxBytes)
+	}
+
+	for i := range prob {
+		if p, w := a.Prob(uint32(i)), want.Prob(uint32(i)); math.Abs(p-w) > 1e-9 {
+			t.Errorf("Prob(%v) was %v, wanted %v", i, p, w)
+		}
+	}
+
+	opts.MaxBytes--
+	if _, err := NewWithOptions(prob, opts); err == nil {
+		t.Errorf("NewWithOptions with too small a MaxBytes did not fail")
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	var r BuildReport
+	_, err := NewWithOptions([]float64{1, 1, 2}, Options{Report: &r})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	want := BuildReport{
+		N:           3,
+		TotalWeight: 4,
+		MinProb:     0.25,
+		MaxProb:     0.5,
+		Entropy:     1.5,
+		FullSlots:   1,
+	}
+	if r.MaxError < 0 || r.MaxError > 1e-9 {
+		t.Errorf("Report.MaxError was %v", r.MaxError)
+	}
+	r.MaxError = 0
+	if r != want {
+		t.Errorf("Report was %+v, wanted %+v", r, want)
+	}
+}
+
+func TestTolerance(t *testing.T) {
+	// 2^-40 can't be represented in the table's 31-bit thresholds
+	prob := []float64{1, 1.0 / (1 << 40)}
+
+	if _, err := NewWithOptions(prob, Options{Tolerance: 1e-14}); err == nil {
+		t.Errorf("NewWithOptions with a tight Tolerance did not fail")
+	}
+	if _, err := NewWithOptions(prob, Options{Tolerance: 1e-8}); err != nil {
+		t.Errorf("NewWithOptions with a loose Tolerance failed: %v", err)
+	}
+}
+
+func TestNewSampler(t *testing.T) {
+	prob := make([]float64, 1000)
+	for i := range prob {
+		prob[i] = float64(i + 1)
+	}
+
+	// room for the wide table
+	var r BuildReport
+	s, err := NewSampler(prob, Options{Report: &r})
+	if err != nil {
+		t.Fatalf("Couldn't NewSampler: %v", err)
+	}
+	if _, ok := s.(*Wide); !ok || r.Compact {
+		t.Errorf("NewSampler without MaxBytes built %T, Compact %v", s, r.Compact)
+	}
+	wideError := r.MaxError
+
+	// room for only the compact table
+	compact := optionsBytes(len(prob), Options{Report: &r}, false, true)
+	s, err = NewSampler(prob, Options{Report: &r, MaxBytes: compact})
+	if err != nil {
+		t.Fatalf("Couldn't NewSampler: %v", err)
+	}
+	if _, ok := s.(*Alias); !ok || !r.Compact {
+		t.Errorf("NewSampler with a small MaxBytes built %T, Compact %v", s, r.Compact)
+	}
+	if r.MaxError <= wideError {
+		t.Errorf("Compact MaxError %v is no worse than wide %v", r.MaxError, wideError)
+	}
+	if s.Len() != len(prob) {
+		t.Errorf("Len was %v, wanted %v", s.Len(), len(prob))
+	}
+
+	if _, err := NewSampler(prob, Options{MaxBytes: 1000}); err == nil {
+		t.Errorf("NewSampler with a tiny MaxBytes did not fail")
+	}
+}
+
+func TestNewSamplerTolerance(t *testing.T) {
+	// 2^-40 is lost by the compact table but kept by the wide one
+	prob := []float64{1, 1.0 / (1 << 40)}
+
+	var r BuildReport
+	s, err := NewSampler(prob, Options{Report: &r, Tolerance: 1e-14})
+	if err != nil {
+		t.Fatalf("Couldn't NewSampler: %v", err)
+	}
+	if _, ok := s.(*Wide); !ok || r.Compact {
+		t.Errorf("NewSampler with a tight Tolerance built %T, Compact %v", s, r.Compact)
+	}
+	if r.MaxError > 1e-14 {
+		t.Errorf("Wide MaxError %v exceeds Tolerance", r.MaxError)
+	}
+
+	// the compact table is good enough
+	s, err = NewSampler(prob, Options{Report: &r, Tolerance: 1e-8})
+	if err != nil {
+		t.Fatalf("Couldn't NewSampler: %v", err)
+	}
+	if _, ok := s.(*Alias); !ok || !r.Compact {
+		t.Errorf("NewSampler with a loose Tolerance built %T, Compact %v", s, r.Compact)
+	}
+
+	// no room to escalate
+	small := optionsBytes(len(prob), Options{Tolerance: 1e-14}, false, true)
+	if _, err := NewSampler(prob, Options{Tolerance: 1e-14, MaxBytes: small}); err == nil {
+		t.Errorf("NewSampler with a tight Tolerance and small MaxBytes did not fail")
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "errors"
+
+// Create a new alias object that keeps only the m heaviest items explicit
+// and lumps the rest into a single "other" item carrying their combined
+// weight, for distributions with huge tails of tiny items.
+//
+// Index j of the table stands for item kept[j], for j < len(kept); kept is
+// in decreasing order of weight, with ties broken by lower index. If any
+// items were lumped together, index len(kept) is the "other" item. cutoff is
+// the weight of the lightest kept item; every lumped item weighs no more.
+//
+// Weights must be positive, as with New, and m must be positive.
+func NewTopOther(prob []float64, m int) (al *Alias, kept []uint32, cutoff float64, err error) {
+	if m < 1 {
+		return nil, nil, 0, errors.New("m must be positive")
+	}
+	if _, err := checkProb(prob, false); err != nil {
+		return nil, nil, 0, err
+	}
+
+	if m > len(prob) {
+		m = len(prob)
+	}
+
+	order := topItems(prob, len(prob))
+	kept = order[:m:m]
+	cutoff = prob[kept[m-1]]
+
+	weights := make([]float64, m, m+1)
+	for j, i := range kept {
+		weights[j] = prob[i]
+	}
+
+	if m < len(prob) {
+		other := float64(0)
+		for _, i := range order[m:] {
+			other += prob[i]
+		}
+		weights = append(weights, other)
+	}
+
+	al, err = New(weights)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return al, kept, cutoff, nil
+}
+
+// Truncate returns a table without the items whose probability in al is
+// below minProb, with their mass spread over the rest in proportion to
+// their probabilities, to shrink tables with long tails of negligible items.
+// removed lists the indices dropped, in increasing order. The remaining
+// items keep their relative order, so index j of the new table is the j-th
+// index of al not in removed.
+//
+// Truncate fails if every item would be removed.
+func (al *Alias) Truncate(minProb float64) (t *Alias, removed []uint32, err error) {
+	pmf := al.pmf()
+
+	var prob []float64
+	for i, p := range pmf {
+		if p < minProb || p <= 0 {
+			removed = append(removed, uint32(i))
+			continue
+		}
+		prob = append(prob, p)
+	}
+
+	if len(prob) == 0 {
+		return nil, nil, errors.New("every item is below minProb")
+	}
+
+	t, err = New(prob)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, removed, nil
+}
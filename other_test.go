@@ -0,0 +1,57 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewTopOther(t *testing.T) {
+	prob := []float64{1, 50, 2, 30, 3, 4}
+
+	a, kept, cutoff, err := NewTopOther(prob, 2)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	if !reflect.DeepEqual(kept, []uint32{1, 3}) || cutoff != 30 {
+		t.Errorf("NewTopOther kept %v with cutoff %v", kept, cutoff)
+	}
+	checkDistribution(t, a.Gen, []float64{50, 30, 10}, 1)
+
+	a, kept, _, err = NewTopOther(prob, 10)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	if len(kept) != len(prob) || len(a.table) != len(prob) {
+		t.Errorf("NewTopOther with m > n kept %v", kept)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	a := mustNew(t, []float64{50, 1, 30, 2, 17})
+
+	tr, removed, err := a.Truncate(0.1)
+	if err != nil {
+		t.Fatalf("Couldn't Truncate: %v", err)
+	}
+	if !reflect.DeepEqual(removed, []uint32{1, 3}) {
+		t.Errorf("Truncate removed %v, wanted [1 3]", removed)
+	}
+	checkProbs(t, tr, []float64{50.0 / 97, 30.0 / 97, 17.0 / 97})
+
+	// nothing to remove
+	tr, removed, err = a.Truncate(0)
+	if err != nil {
+		t.Fatalf("Couldn't Truncate: %v", err)
+	}
+	if len(removed) != 0 || len(tr.table) != 5 {
+		t.Errorf("Truncate(0) removed %v", removed)
+	}
+
+	if _, _, err := a.Truncate(0.9); err == nil {
+		t.Errorf("Truncate of every item did not fail")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+)
+
+// pairTries is how many times GenPairs draws two indices hoping they differ
+// before switching to an exact O(n) method. Repeats are only common when one
+// item holds most of the remaining mass.
+const pairTries = 32
+
+// GenPair draws two distinct indices i and j, with the chance of each
+// ordered pair proportional to Prob(i)*Prob(j). It returns false if fewer
+// than two indices have nonzero probability.
+func (al *Alias) GenPair(rng *rand.Rand) (i, j uint32, ok bool) {
+	pairs := al.GenPairs(rng, 1)
+	if len(pairs) == 0 {
+		return 0, 0, false
+	}
+	return pairs[0][0], pairs[0][1], true
+}
+
+// GenPairs draws up to k disjoint pairs of distinct indices, such as for
+// random graphs or tournament seeding. Each pair is drawn like GenPair, from
+// the indices not used by earlier pairs. It stops early if fewer than two
+// indices with nonzero probability remain.
+func (al *Alias) GenPairs(rng *rand.Rand, k int) [][2]uint32 {
+	var out [][2]uint32
+	withLabel(al.profileLabel, "GenPairs", func(context.Context) {
+		out = al.genPairs(rng, k)
+	})
+	return out
+}
+
+// genPairs does the work of GenPairs.
+func (al *Alias) genPairs(rng *rand.Rand, k int) [][2]uint32 {
+	d := al.BeginDraw()
+
+	var out [][2]uint32
+	for len(out) < k && d.left >= 2 {
+		i, j := d.pair(rng)
+		d.Exclude(i)
+		d.Exclude(j)
+		out = append(out, [2]uint32{i, j})
+	}
+	return out
+}
+
+// pair draws two distinct remaining indices with probability proportional to
+// the product of their probabilities. At least two indices must remain.
+func (d *Draw) pair(rng *rand.Rand) (uint32, uint32) {
+	// two independent draws, conditioned on being different
+	for try := 0; try < pairTries; try++ {
+		i, _ := d.peek(rng)
+		j, _ := d.peek(rng)
+		if i != j {
+			return i, j
+		}
+	}
+
+	// The same thing exactly: i has probability proportional to
+	// p_i*(mass-p_i), and j is then drawn from the rest.
+	weight := func(i int, p float64) float64 {
+		if d.excluded[uint32(i)] {
+			return 0
+		}
+		return p * (d.mass - p)
+	}
+	i := scan(rng, d.pmf, weight)
+	j := scan(rng, d.pmf, func(j int, p float64) float64 {
+		if uint32(j) == i || d.excluded[uint32(j)] {
+			return 0
+		}
+		return p
+	})
+	return i, j
+}
+
+// scan draws an index with probability proportional to weight(i, pmf[i]) by
+// a linear search. At least one weight must be positive.
+func scan(rng *rand.Rand, pmf []float64, weight func(i int, p float64) float64) uint32 {
+	total := float64(0)
+	last := 0
+	for i, p := range pmf {
+		if w := weight(i, p); w > 0 {
+			total += w
+			last = i
+		}
+	}
+
+	x := rng.Float64() * total
+	for i, p := range pmf {
+		w := weight(i, p)
+		if w > 0 && x < w {
+			return uint32(i)
+		}
+		x -= w
+	}
+
+	// rounding error walked us off the end
+	return uint32(last)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGenPair(t *testing.T) {
+	// p = 0.1, 0.2, 0.7; ordered pairs (i, j) with i != j have probability
+	// proportional to p_i*p_j, so i is 0 with probability
+	// 0.1*0.9 / (1 - 0.01 - 0.04 - 0.49) and so on
+	dists := [][]float64{
+		{1, 2, 7},
+		{1, 2, 997}, // exercises the exact fallback
+	}
+	for n, dist := range dists {
+		a, err := New(dist)
+		if err != nil {
+			t.Fatalf("Couldn't create alias: %v", err)
+		}
+
+		total := sum(dist)
+		want := make([]float64, len(dist))
+		for i, w := range dist {
+			p := w / total
+			want[i] = p * (1 - p)
+		}
+
+		checkDistribution(t, func(rng *rand.Rand) uint32 {
+			i, j, ok := a.GenPair(rng)
+			if !ok || i == j {
+				t.Fatalf("GenPair returned %v, %v, %v", i, j, ok)
+			}
+			return i
+		}, want, int64(n))
+	}
+}
+
+func TestGenPairs(t *testing.T) {
+	a, err := New([]float64{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 1000; n++ {
+		pairs := a.GenPairs(rng, 3)
+		if len(pairs) != 2 {
+			t.Fatalf("GenPairs(3) over 5 items returned %v", pairs)
+		}
+		seen := make(map[uint32]bool)
+		for _, p := range pairs {
+			if seen[p[0]] || seen[p[1]] || p[0] == p[1] {
+				t.Fatalf("GenPairs returned overlapping pairs %v", pairs)
+			}
+			seen[p[0]] = true
+			seen[p[1]] = true
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+)
+
+// SeedSequence derives seeds for any number of workers from one master
+// seed, so a parallel run can be repeated exactly from the master seed
+// alone. The seed for each worker index depends only on the master seed
+// and the index.
+type SeedSequence struct {
+	master uint64
+}
+
+// NewSeedSequence returns a SeedSequence derived from master.
+func NewSeedSequence(master int64) SeedSequence {
+	return SeedSequence{uint64(master)}
+}
+
+// Seed returns the seed for worker i. Seeds are spread by SplitMix64, so
+// nearby indices and nearby master seeds give unrelated seeds.
+func (s SeedSequence) Seed(i uint64) int64 {
+	return int64(splitmix64(s.master + (i+1)*weylStep))
+}
+
+// Rand returns a new generator seeded with Seed(i).
+func (s SeedSequence) Rand(i uint64) *rand.Rand {
+	return rand.New(rand.NewSource(s.Seed(i)))
+}
+
+// splitmix64 is the output function of Steele, Lea and Flood's SplitMix64.
+func splitmix64(z uint64) uint64 {
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	return z ^ z>>31
+}
+
+// parallelChunk is the number of draws GenParallel makes with each
+// generator.
+const parallelChunk = 4096
+
+// GenParallel fills out with draws, using up to workers goroutines (at
+// least one). out is split into chunks of a fixed size, and chunk k is
+// filled by a generator from seq.Rand(k), so the result depends only on
+// seq and len(out), and not on workers, GOMAXPROCS or scheduling.
+func (al *Alias) GenParallel(seq SeedSequence, out []uint32, workers int) {
+	// the workers inherit the labels
+	withLabel(al.profileLabel, "GenParallel", func(context.Context) {
+		al.genParallel(seq, out, workers)
+	})
+}
+
+// genParallel does the work of GenParallel.
+func (al *Alias) genParallel(seq SeedSequence, out []uint32, workers int) {
+	chunks := (len(out) + parallelChunk - 1) / parallelChunk
+	if workers > chunks {
+		workers = chunks
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	var mu sync.Mutex
+	next := 0
+	take := func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if next >= chunks {
+			return 0, false
+		}
+		next++
+		return next - 1, true
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				k, ok := take()
+				if !ok {
+					return
+				}
+				rng := seq.Rand(uint64(k))
+				end := (k + 1) * parallelChunk
+				if end > len(out) {
+					end = len(out)
+				}
+				for i := k * parallelChunk; i < end; i++ {
+					out[i] = al.Gen(rng)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeedSequence(t *testing.T) {
+	seq := NewSeedSequence(1)
+
+	seen := make(map[int64]bool)
+	for i := uint64(0); i < 1000; i++ {
+		s := seq.Seed(i)
+		if seen[s] {
+			t.Fatalf("Seed(%v) repeated %v", i, s)
+		}
+		seen[s] = true
+	}
+
+	if NewSeedSequence(1).Seed(5) != seq.Seed(5) {
+		t.Errorf("Seed is not deterministic")
+	}
+	if NewSeedSequence(2).Seed(5) == seq.Seed(5) {
+		t.Errorf("Different master seeds gave the same seed")
+	}
+}
+
+func TestGenParallel(t *testing.T) {
+	a := mustNew(t, []float64{10, 1, 2, 3})
+	seq := NewSeedSequence(7)
+
+	want := make([]uint32, 3*parallelChunk+17)
+	a.GenParallel(seq, want, 1)
+
+	for _, workers := range []int{0, 2, 3, 16} {
+		out := make([]uint32, len(want))
+		a.GenParallel(seq, out, workers)
+		if !reflect.DeepEqual(out, want) {
+			t.Errorf("GenParallel with %v workers differs from 1 worker", workers)
+		}
+	}
+
+	counts := make([]int, 4)
+	for _, v := range want {
+		counts[v]++
+	}
+	if counts[0] < counts[3] || counts[3] < counts[1] {
+		t.Errorf("GenParallel draws look wrong: counts %v", counts)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Poisson generates a stream of events over virtual time in which each index
+// fires as an independent Poisson process, with index i firing at rate
+// Rate*Prob(i). This is the superposition of those processes: events arrive
+// at the total rate with exponential gaps, and each is labeled by a draw
+// from the table. It suits traffic and workload simulators.
+type Poisson struct {
+	al   *Alias
+	rate float64
+	now  float64
+}
+
+// NewPoisson returns a stream over al whose events arrive at a total rate of
+// rate per unit of virtual time, starting at time zero.
+func NewPoisson(al *Alias, rate float64) (*Poisson, error) {
+	if !(rate > 0) || math.IsInf(rate, 0) {
+		return nil, errors.New("rate must be positive and finite")
+	}
+	return &Poisson{al: al, rate: rate}, nil
+}
+
+// Now returns the time of the most recent event, or zero if there has been
+// none.
+func (p *Poisson) Now() float64 {
+	return p.now
+}
+
+// Next advances to the next event and returns its time and index.
+func (p *Poisson) Next(rng *rand.Rand) (float64, uint32) {
+	p.now += rng.ExpFloat64() / p.rate
+	return p.now, p.al.Gen(rng)
+}
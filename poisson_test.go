@@ -0,0 +1,43 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPoisson(t *testing.T) {
+	dist := []float64{1, 3}
+	a, err := New(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	p, err := NewPoisson(a, 50)
+	if err != nil {
+		t.Fatalf("Couldn't create Poisson: %v", err)
+	}
+
+	last := float64(0)
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		now, i := p.Next(rng)
+		if now <= last {
+			t.Fatalf("Time went from %v to %v", last, now)
+		}
+		last = now
+		return i
+	}, dist, 1)
+
+	// a million events at rate 50 take about 20000 time units
+	if math.Abs(p.Now()-distributionCount/50) > 100 {
+		t.Errorf("%v events took %v time units at rate 50", distributionCount, p.Now())
+	}
+
+	if _, err := NewPoisson(a, 0); err == nil {
+		t.Errorf("NewPoisson with rate 0 did not fail")
+	}
+}
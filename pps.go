@@ -0,0 +1,144 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// GenPPS draws a sample of exactly n distinct indices with probability
+// proportional to size (PPS), as used in survey sampling: each index is
+// included with probability n*Prob(i), or with certainty if that's 1 or
+// more (the rest then share the remaining draws in the same way).
+//
+// The sample is drawn systematically: the indices' inclusion probabilities
+// are laid end to end in index order, and the indices hit by a random start
+// in [0,1) and every point a whole step past it are chosen. So the joint
+// inclusion of indices depends on their order; sort or shuffle the items
+// before building the table if that matters.
+//
+// The result is in increasing order. GenPPS fails if fewer than n indices
+// have nonzero probability.
+func (al *Alias) GenPPS(rng *rand.Rand, n int) ([]uint32, error) {
+	var out []uint32
+	var err error
+	withLabel(al.profileLabel, "GenPPS", func(context.Context) {
+		out, err = al.genPPS(rng, n)
+	})
+	return out, err
+}
+
+// genPPS does the work of GenPPS.
+func (al *Alias) genPPS(rng *rand.Rand, n int) ([]uint32, error) {
+	pi, err := ppsInclusion(al.pmf(), al.pmfLive, n)
+	if err != nil {
+		return nil, err
+	}
+
+	// points(x) is the number of points u, u+1, u+2, ... below x
+	u := rng.Float64()
+	points := func(x float64) float64 {
+		if x <= u {
+			return 0
+		}
+		return math.Ceil(x - u)
+	}
+
+	out := make([]uint32, 0, n)
+	cum := float64(0)
+	for i, p := range pi {
+		if len(out) == n {
+			break
+		}
+		if p >= 1 {
+			out = append(out, uint32(i))
+			continue
+		}
+
+		next := cum + p
+		if p > 0 && points(next) > points(cum) {
+			out = append(out, uint32(i))
+		}
+		cum = next
+	}
+
+	// rounding can make the last point fall just past the end
+	for i := len(pi) - 1; len(out) < n; i-- {
+		if pi[i] > 0 && !containsSorted(out, uint32(i)) {
+			out = insertSorted(out, uint32(i))
+		}
+	}
+
+	return out, nil
+}
+
+// ppsInclusion returns the PPS inclusion probabilities for a sample of n
+// from pmf, which has live nonzero entries.
+func ppsInclusion(pmf []float64, live, n int) ([]float64, error) {
+	if n < 0 || n > live {
+		return nil, errors.New("sample size out of range")
+	}
+
+	pi := make([]float64, len(pmf))
+	certain := make([]bool, len(pmf))
+
+	// Items at or over certainty are taken outright, which leaves fewer
+	// draws for the rest and can push more of them over, so repeat until
+	// none are added.
+	for {
+		rest := float64(0)
+		m := n
+		for i, p := range pmf {
+			if certain[i] {
+				m--
+			} else {
+				rest += p
+			}
+		}
+
+		changed := false
+		for i, p := range pmf {
+			if certain[i] {
+				pi[i] = 1
+				continue
+			}
+			pi[i] = 0
+			if rest > 0 {
+				pi[i] = float64(m) * p / rest
+			}
+			if pi[i] >= 1 {
+				certain[i] = true
+				changed = true
+			}
+		}
+
+		if !changed {
+			return pi, nil
+		}
+	}
+}
+
+func containsSorted(s []uint32, v uint32) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func insertSorted(s []uint32, v uint32) []uint32 {
+	i := len(s)
+	for i > 0 && s[i-1] > v {
+		i--
+	}
+	s = append(s, 0)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGenPPS(t *testing.T) {
+	// with n = 2, item 0 (p = 0.6) is certain, and the other draw is shared
+	// by the rest in proportion 1:2:1
+	a, err := New([]float64{6, 1, 2, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	want := []float64{1, 0.25, 0.5, 0.25}
+
+	rng := rand.New(rand.NewSource(1))
+	const trials = 100000
+	counts := make([]float64, len(want))
+	for n := 0; n < trials; n++ {
+		sample, err := a.GenPPS(rng, 2)
+		if err != nil {
+			t.Fatalf("GenPPS failed: %v", err)
+		}
+		if len(sample) != 2 || sample[0] >= sample[1] {
+			t.Fatalf("GenPPS returned %v", sample)
+		}
+		for _, i := range sample {
+			counts[i]++
+		}
+	}
+
+	for i, w := range want {
+		if got := counts[i] / trials; math.Abs(got-w) > 0.01 {
+			t.Errorf("Item %v was included %v of the time, wanted %v", i, got, w)
+		}
+	}
+
+	if _, err := a.GenPPS(rng, 5); err == nil {
+		t.Errorf("GenPPS with n larger than the population did not fail")
+	}
+}
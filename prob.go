@@ -0,0 +1,221 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math"
+)
+
+// Prob returns the probability that Gen returns i, as encoded in the table.
+// This includes the effect of quantizing each slot's threshold, so it may
+// differ very slightly from the probability the table was built from.
+//
+// The probabilities are computed in O(n) on the first call to Prob or any
+// other method that needs them, and cached.
+func (al *Alias) Prob(i uint32) float64 {
+	pmf := al.pmf()
+	if int(i) >= len(pmf) {
+		return 0
+	}
+	return pmf[i]
+}
+
+// LogProb returns the natural logarithm of Prob(i).
+func (al *Alias) LogProb(i uint32) float64 {
+	return math.Log(al.Prob(i))
+}
+
+// CDF returns the probability that Gen returns an index less than or equal
+// to i.
+//
+// Like Prob, the first call costs O(n), and the results are cached.
+func (al *Alias) CDF(i uint32) float64 {
+	cdf := al.cdf()
+	if int(i) >= len(cdf) {
+		return 1
+	}
+	return cdf[i]
+}
+
+// pmf returns the probability the table gives each index. It is computed on
+// first use and cached.
+func (al *Alias) pmf() []float64 {
+	al.pmfOnce.Do(func() {
+		withLabel(al.profileLabel, "pmf", func(context.Context) {
+			al.pmfCache = tablePMF(al.table)
+		})
+		for _, p := range al.pmfCache {
+			if p > 0 {
+				al.pmfLive++
+			}
+		}
+	})
+	return al.pmfCache
+}
+
+// cdf returns the running sums of pmf. It is computed on first use and
+// cached.
+func (al *Alias) cdf() []float64 {
+	al.cdfOnce.Do(func() {
+		pmf := al.pmf()
+		cdf := make([]float64, len(pmf))
+		total := float64(0)
+		for i, p := range pmf {
+			total += p
+			cdf[i] = total
+		}
+		al.cdfCache = cdf
+	})
+	return al.cdfCache
+}
+
+// tablePMF computes the exact probability of Gen returning each index.
+//
+// Gen draws ri uniformly from [0,2^31), uses slot ri%n, and returns the slot's
+// own index if ri <= prob and its alias otherwise. So the chance of each
+// outcome is a count of values of ri over 2^31, and counting them gives the
+// probabilities exactly, including the effects of quantization and of n not
+// dividing 2^31 evenly.
+func tablePMF(table []ipiece) []float64 {
+	pmf := make([]float64, len(table))
+	for i, c := range tableCounts(table) {
+		pmf[i] = float64(c) / (1 << 31)
+	}
+	return pmf
+}
+
+// tableCounts returns, for each index, how many of the 2^31 values Gen can
+// draw return it.
+func tableCounts(table []ipiece) []uint64 {
+	counts := make([]uint64, len(table))
+	for w, piece := range table {
+		direct, total := slotCounts(table, uint32(w))
+		counts[w] += direct
+		counts[piece.alias] += total - direct
+	}
+	return counts
+}
+
+// slotCounts returns how many of the 2^31 values Gen can draw land in slot
+// w, and how many of those return the slot's own index.
+func slotCounts(table []ipiece, w uint32) (direct, total uint64) {
+	const max = 1<<31 - 1
+
+	if w > max {
+		// slots past 2^31 can never be hit
+		return 0, 0
+	}
+
+	n := uint64(len(table))
+	total = (max-uint64(w))/n + 1
+
+	if table[w].prob >= w && table[w].prob > 0 {
+		direct = uint64(table[w].prob-w)/n + 1
+	}
+	return direct, total
+}
+
+// fromCounts is the inverse of tableCounts: it builds a table in which each
+// index i is returned by exactly counts[i] of the 2^31 values Gen can draw.
+// counts must sum to 2^31.
+//
+// It is Vose's algorithm in whole numbers of values, where slot w holds the
+// total from slotCounts rather than one nth of the mass. Slot totals differ
+// by at most one, so an item with exactly its slot's total fills it, and
+// only items with more are used to fill other slots.
+func fromCounts(counts []uint64) *Alias {
+	const max = 1<<31 - 1
+
+	n := uint64(len(counts))
+	table := make([]ipiece, n)
+	slotTotal := func(w uint32) uint64 {
+		if w > max {
+			return 0
+		}
+		return (max-uint64(w))/n + 1
+	}
+
+	// set gives slot w direct values for its own index, and the rest of
+	// its values for alias
+	set := func(w uint32, direct uint64, alias uint32) {
+		prob := uint64(0)
+		switch {
+		case direct == slotTotal(w):
+			prob = max
+		case direct > 0:
+			prob = uint64(w) + (direct-1)*n
+			if prob == 0 {
+				// slot 0 keeps only ri == 0, and a threshold of zero
+				// would keep nothing
+				prob = n - 1
+			}
+		}
+		table[w] = ipiece{uint32(prob), alias}
+	}
+
+	left := append([]uint64(nil), counts...)
+	var small, large []uint32
+	for i, c := range left {
+		switch t := slotTotal(uint32(i)); {
+		case c < t:
+			small = append(small, uint32(i))
+		case c > t:
+			large = append(large, uint32(i))
+		default:
+			set(uint32(i), c, 0)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+
+		set(s, left[s], g)
+		left[g] -= slotTotal(s) - left[s]
+
+		switch t := slotTotal(g); {
+		case left[g] < t:
+			large = large[:len(large)-1]
+			small = append(small, g)
+		case left[g] == t:
+			large = large[:len(large)-1]
+			set(g, t, 0)
+		}
+	}
+
+	// with counts summing to 2^31, nothing is left over
+	for _, i := range append(small, large...) {
+		set(i, slotTotal(i), 0)
+	}
+
+	return &Alias{table: table}
+}
+
+// Outcome is an index and the exact probability the table gives it.
+type Outcome struct {
+	Index uint32
+	Prob  float64
+}
+
+// EnumerateDraws returns the k most likely outcomes of Gen, most likely
+// first with ties broken by index, along with their probabilities. Nothing
+// is sampled: the probabilities are exact (each is a whole number of
+// 2^-31ths, which a float64 holds exactly), so tests can assert on a
+// table's configuration without depending on random draws. Outcomes with
+// probability zero are left out, and k <= 0 gives nil.
+func (al *Alias) EnumerateDraws(k int) []Outcome {
+	pmf := al.pmf()
+
+	var out []Outcome
+	for _, i := range topItems(pmf, k) {
+		if pmf[i] <= 0 {
+			break
+		}
+		out = append(out, Outcome{i, pmf[i]})
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestProb(t *testing.T) {
+	dists := [][]float64{
+		{1},
+		{1, 1},
+		{1, 2, 3},
+		{9, 8, 1, 4, 2},
+		{1000, 1, 3, 10},
+	}
+	for _, dist := range dists {
+		a, err := New(dist)
+		if err != nil {
+			t.Fatalf("Couldn't create alias: %v", err)
+		}
+
+		total := sum(dist)
+		sumP := float64(0)
+		for i, w := range dist {
+			p := a.Prob(uint32(i))
+			sumP += p
+			if math.Abs(p-w/total) > 1e-8 {
+				t.Errorf("Prob(%v) for %v was %v, wanted %v", i, dist, p, w/total)
+			}
+			if math.Abs(a.LogProb(uint32(i))-math.Log(p)) > 1e-12 {
+				t.Errorf("LogProb(%v) for %v didn't match Prob", i, dist)
+			}
+		}
+		if sumP != 1 {
+			t.Errorf("Probabilities for %v summed to %v", dist, sumP)
+		}
+
+		if p := a.Prob(uint32(len(dist))); p != 0 {
+			t.Errorf("Prob out of range was %v", p)
+		}
+	}
+}
+
+func TestCDF(t *testing.T) {
+	a, err := New([]float64{1, 2, 3, 2})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	want := []float64{0.125, 0.375, 0.75, 1, 1}
+	for i, w := range want {
+		if c := a.CDF(uint32(i)); math.Abs(c-w) > 1e-8 {
+			t.Errorf("CDF(%v) was %v, wanted %v", i, c, w)
+		}
+	}
+}
+
+func TestEnumerateDraws(t *testing.T) {
+	a, err := fromPMF([]float64{1, 2, 0, 4, 1})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	want := []Outcome{{3, 0.5}, {1, 0.25}, {0, 0.125}, {4, 0.125}}
+	if got := a.EnumerateDraws(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnumerateDraws(10) was %v, wanted %v", got, want)
+	}
+	if got := a.EnumerateDraws(2); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("EnumerateDraws(2) was %v, wanted %v", got, want[:2])
+	}
+	for _, k := range []int{0, -1} {
+		if got := a.EnumerateDraws(k); got != nil {
+			t.Errorf("EnumerateDraws(%v) was %v, wanted nil", k, got)
+		}
+	}
+}
+
+func TestFromCounts(t *testing.T) {
+	for _, prob := range [][]float64{
+		{1},
+		{1, 2, 3, 4},
+		{1e-9, 1, 7, 1e-9, 2},
+		{0.3, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1},
+	} {
+		a := mustNew(t, prob)
+		want := tableCounts(a.table)
+		if got := tableCounts(fromCounts(want).table); !reflect.DeepEqual(got, want) {
+			t.Errorf("fromCounts(%v) gave counts %v", want, got)
+		}
+	}
+
+	// slot 0 keeping only ri == 0 can't use a threshold of zero
+	want := []uint64{1, 1<<31 - 1}
+	if got := tableCounts(fromCounts(want).table); !reflect.DeepEqual(got, want) {
+		t.Errorf("fromCounts(%v) gave counts %v", want, got)
+	}
+}
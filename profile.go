@@ -0,0 +1,39 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+//go:build !tinygo
+
+package alias
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// withLabel runs f with the pprof labels described by Options.ProfileLabel,
+// or just runs f if label is empty. f is passed the labeled context.
+//
+// pprof.Do on the calling goroutine would replace its own labels during f,
+// and reset them to those of the context it was given afterwards. The
+// caller's labels can't be read back without a context carrying them, so
+// instead f runs on a new goroutine, and the caller's labels are left
+// alone. A panic in f is passed on to the caller.
+func withLabel(label, op string, f func(context.Context)) {
+	if label == "" {
+		f(context.Background())
+		return
+	}
+
+	labels := pprof.Labels("alias", label, "alias.op", op)
+	done := make(chan interface{})
+	go func() {
+		defer func() {
+			done <- recover()
+		}()
+		pprof.Do(context.Background(), labels, f)
+	}()
+	if p := <-done; p != nil {
+		panic(p)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+//go:build !tinygo
+
+package alias
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+func TestProfileLabel(t *testing.T) {
+	ran := false
+	withLabel("", "build", func(ctx context.Context) {
+		ran = true
+		if v, ok := pprof.Label(ctx, "alias"); ok {
+			t.Errorf("withLabel with no label set alias to %q", v)
+		}
+	})
+	if !ran {
+		t.Errorf("withLabel with no label did not run f")
+	}
+
+	ran = false
+	withLabel("markets", "build", func(ctx context.Context) {
+		ran = true
+		if v, _ := pprof.Label(ctx, "alias"); v != "markets" {
+			t.Errorf("alias label was %q, wanted markets", v)
+		}
+		if v, _ := pprof.Label(ctx, "alias.op"); v != "build" {
+			t.Errorf("alias.op label was %q, wanted build", v)
+		}
+	})
+	if !ran {
+		t.Errorf("withLabel did not run f")
+	}
+
+	a, err := NewWithOptions([]float64{1, 2}, Options{ProfileLabel: "markets"})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+	if a.profileLabel != "markets" {
+		t.Errorf("profileLabel was %q", a.profileLabel)
+	}
+
+	s, err := NewSampler([]float64{1, 2}, Options{ProfileLabel: "markets"})
+	if err != nil {
+		t.Fatalf("Couldn't NewSampler: %v", err)
+	}
+	if wd, ok := s.(*Wide); !ok || wd.profileLabel != "markets" {
+		t.Errorf("NewSampler built %T without the label", s)
+	}
+}
+
+// goroutineLabels returns the labels line of the goroutine profile record
+// for the goroutine running fn.
+func goroutineLabels(t *testing.T, fn string) string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		t.Fatalf("Couldn't write goroutine profile: %v", err)
+	}
+	for _, rec := range strings.Split(buf.String(), "\n\n") {
+		if !strings.Contains(rec, fn) {
+			continue
+		}
+		for _, line := range strings.Split(rec, "\n") {
+			if strings.HasPrefix(line, "# labels: ") {
+				return line
+			}
+		}
+		return ""
+	}
+	t.Fatalf("No goroutine running %v", fn)
+	return ""
+}
+
+func TestProfileLabelKeepsCallerLabels(t *testing.T) {
+	a, err := NewWithOptions([]float64{1, 2, 3}, Options{ProfileLabel: "markets"})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("app", "checkout"))
+	pprof.SetGoroutineLabels(ctx)
+	defer pprof.SetGoroutineLabels(context.Background())
+
+	a.DrawCounts(rand.New(rand.NewSource(1)), 100)
+	a.Prob(0)
+
+	want := `# labels: {"app":"checkout"}`
+	if got := goroutineLabels(t, "TestProfileLabelKeepsCallerLabels"); got != want {
+		t.Errorf("Labels after DrawCounts were %q, wanted %q", got, want)
+	}
+}
+
+func TestProfileLabelPanic(t *testing.T) {
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("Recovered %v, wanted boom", p)
+		}
+	}()
+	withLabel("markets", "build", func(context.Context) { panic("boom") })
+}
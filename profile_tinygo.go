@@ -0,0 +1,14 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+//go:build tinygo
+
+package alias
+
+import "context"
+
+// withLabel just runs f; TinyGo doesn't support pprof labels.
+func withLabel(label, op string, f func(context.Context)) {
+	f(context.Background())
+}
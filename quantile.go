@@ -0,0 +1,53 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "math"
+
+// Quantile returns the smallest index i with CDF(i) > u, which is the
+// inverse of the CDF. Feeding it uniform values in [0,1) gives the same
+// distribution as Gen, but preserves the ordering of u, so it suits
+// stratified and quasi-Monte Carlo sampling. u is clamped to [0,1).
+//
+// Quantile uses a guide table (Chen and Asau's method) so each lookup takes
+// expected O(1) time. The guide table and the cumulative probabilities it
+// indexes are built in O(n) on first use and cached.
+func (al *Alias) Quantile(u float64) uint32 {
+	if !(u >= 0) {
+		u = 0
+	} else if u >= 1 {
+		u = math.Nextafter(1, 0)
+	}
+
+	cdf := al.cdf()
+	guide := al.guide()
+
+	i := guide[int(u*float64(len(guide)))]
+	for cdf[i] <= u {
+		i++
+	}
+	return i
+}
+
+// guide returns, for each k in [0,n), the smallest index i with
+// cdf[i] > k/n. It is computed on first use and cached.
+func (al *Alias) guide() []uint32 {
+	al.guideOnce.Do(func() {
+		cdf := al.cdf()
+		m := len(cdf)
+		guide := make([]uint32, m)
+
+		i := 0
+		for k := range guide {
+			for cdf[i] <= float64(k)/float64(m) {
+				i++
+			}
+			guide[k] = uint32(i)
+		}
+
+		al.guideCache = guide
+	})
+	return al.guideCache
+}
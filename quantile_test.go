@@ -0,0 +1,41 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestQuantile(t *testing.T) {
+	dist := []float64{1000, 1, 3, 10, 5, 5}
+	a, err := New(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		return a.Quantile(rng.Float64())
+	}, dist, 1)
+
+	// agrees with a linear search of the CDF
+	for j := 0; j <= 1000; j++ {
+		u := float64(j) / 1000
+		want := uint32(0)
+		for a.CDF(want) <= u && int(want) < len(dist)-1 {
+			want++
+		}
+		if got := a.Quantile(u); got != want {
+			t.Errorf("Quantile(%v) was %v, wanted %v", u, got, want)
+		}
+	}
+
+	if q := a.Quantile(-1); q != 0 {
+		t.Errorf("Quantile(-1) was %v", q)
+	}
+	if q := a.Quantile(2); q != uint32(len(dist)-1) {
+		t.Errorf("Quantile(2) was %v", q)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+// Quota draws from a table while enforcing a quota on the number of times
+// each index may be drawn, as for allocating limited stock. Once an index
+// has used up its quota it's excluded, and the others are renormalized.
+//
+// A Quota is safe for concurrent use, provided each goroutine uses its own
+// rng.
+type Quota struct {
+	mu     sync.Mutex
+	d      *Draw
+	counts []uint64
+	quotas []uint64
+}
+
+// NewQuota returns a Quota drawing from al, where index i may be drawn
+// quotas[i] times. quotas must have one entry per index of al.
+func NewQuota(al *Alias, quotas []uint64) (*Quota, error) {
+	if len(quotas) != len(al.table) {
+		return nil, errors.New("wrong number of quotas")
+	}
+
+	q := &Quota{
+		d:      al.BeginDraw(),
+		counts: make([]uint64, len(quotas)),
+		quotas: append([]uint64(nil), quotas...),
+	}
+	for i, quota := range quotas {
+		if quota == 0 {
+			q.d.Exclude(uint32(i))
+		}
+	}
+	return q, nil
+}
+
+// Gen draws an index with quota remaining, according to the distribution
+// renormalized over such indices, and counts it against its quota. It
+// returns false once every index has used up its quota.
+func (q *Quota) Gen(rng *rand.Rand) (uint32, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i, ok := q.d.peek(rng)
+	if !ok {
+		return 0, false
+	}
+
+	q.counts[i]++
+	if q.counts[i] >= q.quotas[i] {
+		q.d.Exclude(i)
+	}
+	return i, true
+}
+
+// Remaining returns how many more times i may be drawn.
+func (q *Quota) Remaining(i uint32) uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if int(i) >= len(q.quotas) {
+		return 0
+	}
+	return q.quotas[i] - q.counts[i]
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestQuota(t *testing.T) {
+	a := mustNew(t, []float64{10, 1, 1})
+
+	q, err := NewQuota(a, []uint64{2, 5, 0})
+	if err != nil {
+		t.Fatalf("Couldn't create Quota: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	counts := make([]uint64, 3)
+	for {
+		i, ok := q.Gen(rng)
+		if !ok {
+			break
+		}
+		counts[i]++
+	}
+
+	if counts[0] != 2 || counts[1] != 5 || counts[2] != 0 {
+		t.Errorf("Drew %v, wanted [2 5 0]", counts)
+	}
+	for i := uint32(0); i < 3; i++ {
+		if r := q.Remaining(i); r != 0 {
+			t.Errorf("Remaining(%v) was %v", i, r)
+		}
+	}
+
+	// until quotas run out, draws follow the distribution
+	q, err = NewQuota(a, []uint64{1 << 40, 1 << 40, 1 << 40})
+	if err != nil {
+		t.Fatalf("Couldn't create Quota: %v", err)
+	}
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		i, _ := q.Gen(rng)
+		return i
+	}, []float64{10, 1, 1}, 2)
+
+	if _, err := NewQuota(a, []uint64{1}); err == nil {
+		t.Errorf("NewQuota with too few quotas did not fail")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// GenInRange generates a random number in [lo, hi] according to the
+// distribution restricted to that range and renormalized. It returns false
+// if the range has no probability mass.
+//
+// Ranges holding at least half the mass are drawn by rejection from Gen.
+// Smaller ranges are drawn by binary search over the cumulative
+// probabilities, which are computed in O(n) on first use and cached.
+func (al *Alias) GenInRange(rng *rand.Rand, lo, hi uint32) (uint32, bool) {
+	n := uint32(len(al.table))
+	if hi >= n {
+		hi = n - 1
+	}
+	if lo > hi {
+		return 0, false
+	}
+
+	cdf := al.cdf()
+
+	below := float64(0)
+	if lo > 0 {
+		below = cdf[lo-1]
+	}
+	mass := cdf[hi] - below
+	if mass <= 0 {
+		return 0, false
+	}
+
+	if mass >= 0.5 {
+		for {
+			i := al.Gen(rng)
+			if i >= lo && i <= hi {
+				return i, true
+			}
+		}
+	}
+
+	u := below + rng.Float64()*mass
+	i := lo + uint32(sort.Search(int(hi-lo), func(j int) bool {
+		return cdf[lo+uint32(j)] > u
+	}))
+	return i, true
+}
+
+// GenBelow generates a random number in [0, limit) according to the
+// distribution restricted to that prefix and renormalized, such as to draw
+// only from items released so far. It returns false if the prefix has no
+// probability mass.
+func (al *Alias) GenBelow(rng *rand.Rand, limit uint32) (uint32, bool) {
+	if limit == 0 {
+		return 0, false
+	}
+	return al.GenInRange(rng, 0, limit-1)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGenInRange(t *testing.T) {
+	a, err := New([]float64{10, 1, 2, 3, 20, 4})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	genRange := func(lo, hi uint32) func(*rand.Rand) uint32 {
+		return func(rng *rand.Rand) uint32 {
+			i, ok := a.GenInRange(rng, lo, hi)
+			if !ok {
+				t.Fatalf("GenInRange(%v, %v) failed", lo, hi)
+			}
+			return i
+		}
+	}
+
+	// small range, by binary search
+	checkDistribution(t, genRange(1, 3), []float64{0, 1, 2, 3, 0, 0}, 1)
+	// large range, by rejection
+	checkDistribution(t, genRange(0, 4), []float64{10, 1, 2, 3, 20, 0}, 2)
+	// past the end
+	checkDistribution(t, genRange(5, 100), []float64{0, 0, 0, 0, 0, 1}, 3)
+
+	if _, ok := a.GenInRange(rand.New(rand.NewSource(1)), 3, 2); ok {
+		t.Errorf("GenInRange with lo > hi did not fail")
+	}
+}
+
+func TestGenBelow(t *testing.T) {
+	a, err := New([]float64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("Couldn't create alias: %v", err)
+	}
+
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		i, ok := a.GenBelow(rng, 3)
+		if !ok {
+			t.Fatalf("GenBelow(3) failed")
+		}
+		return i
+	}, []float64{1, 2, 3, 0}, 1)
+
+	if _, ok := a.GenBelow(rand.New(rand.NewSource(1)), 0); ok {
+		t.Errorf("GenBelow(0) did not fail")
+	}
+}
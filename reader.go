@@ -0,0 +1,63 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// Reader is an io.Reader producing an endless stream of draws as text, one
+// per line. With an rng seeded the same way it produces the same stream, so
+// a Go binary can feed reproducible weighted sequences to a shell pipeline
+// or test harness. Use io.LimitReader (or head -n) to end the stream.
+//
+// A Reader is not safe for concurrent use.
+type Reader struct {
+	al      *Alias
+	rng     *rand.Rand
+	labels  []string
+	line    []byte
+	pending []byte
+}
+
+// NewReader returns a Reader writing the decimal indices drawn from al.
+func NewReader(al *Alias, rng *rand.Rand) *Reader {
+	return &Reader{al: al, rng: rng}
+}
+
+// NewReader returns a Reader writing the keys drawn from k. Keys are
+// written as they are, so keys containing newlines make for ambiguous
+// output.
+func (k *Keyed) NewReader(rng *rand.Rand) *Reader {
+	return &Reader{al: k.al, rng: rng, labels: k.keys}
+}
+
+// Read implements io.Reader. It always fills p and never returns an error.
+// A line split between two calls is finished on the next one.
+func (r *Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.pending) == 0 {
+			r.line = r.appendDraw(r.line[:0])
+			r.pending = r.line
+		}
+		c := copy(p[n:], r.pending)
+		r.pending = r.pending[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// appendDraw appends one draw and its newline to b.
+func (r *Reader) appendDraw(b []byte) []byte {
+	i := r.al.Gen(r.rng)
+	if r.labels != nil {
+		b = append(b, r.labels[i]...)
+	} else {
+		b = strconv.AppendUint(b, uint64(i), 10)
+	}
+	return append(b, '\n')
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 3})
+
+	// the stream matches Gen with the same seed
+	rng := rand.New(rand.NewSource(5))
+	var want bytes.Buffer
+	for i := 0; i < 1000; i++ {
+		want.WriteString(strconv.Itoa(int(a.Gen(rng))) + "\n")
+	}
+
+	// read in odd sized pieces, so lines get split between reads
+	r := NewReader(a, rand.New(rand.NewSource(5)))
+	var got bytes.Buffer
+	buf := make([]byte, 3)
+	for got.Len() < want.Len() {
+		n, err := r.Read(buf)
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		got.Write(buf[:n])
+	}
+	if !bytes.Equal(got.Bytes()[:want.Len()], want.Bytes()) {
+		t.Errorf("Reader stream didn't match Gen")
+	}
+}
+
+func TestKeyedReader(t *testing.T) {
+	k, err := NewKeyedMap(map[string]float64{"heads": 1, "tails": 3})
+	if err != nil {
+		t.Fatalf("Couldn't create keyed: %v", err)
+	}
+
+	sc := bufio.NewScanner(io.LimitReader(k.NewReader(rand.New(rand.NewSource(1))), 60000))
+	counts := make(map[string]int)
+	for sc.Scan() {
+		counts[sc.Text()]++
+	}
+	if len(counts) > 3 || counts["heads"] < 2000 || counts["tails"] < 6000 {
+		t.Errorf("Got unexpected lines: %v", counts)
+	}
+}
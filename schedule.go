@@ -0,0 +1,133 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ScheduleItem is an item of a Schedule: a weight, and the window of time
+// [Start, End) during which the item may be picked. A zero Start means the
+// item has always been active, and a zero End that it stays active forever.
+type ScheduleItem struct {
+	Weight     float64
+	Start, End time.Time
+}
+
+// Schedule picks items whose windows of activity contain a given time, such
+// as campaigns in a rotation, according to their weights renormalized over
+// the items active at that time.
+//
+// The windows' start and end times divide time into segments within which
+// the active items don't change. Each segment's table is built the first
+// time a draw falls in it and kept, so draws cost a binary search over the
+// segments plus a draw from a table, with no rebuilding as time passes.
+//
+// A Schedule is safe for concurrent use.
+type Schedule struct {
+	items  []ScheduleItem
+	bounds []time.Time // distinct start and end times, sorted
+	segs   []segment   // segs[k] covers [bounds[k-1], bounds[k])
+}
+
+type segment struct {
+	once sync.Once
+	al   *Alias   // nil if no items are active
+	idx  []uint32 // indices of al's items in the Schedule
+}
+
+// NewSchedule returns a Schedule over items. Weights must be positive, and
+// each window's End must be after its Start.
+func NewSchedule(items []ScheduleItem) (*Schedule, error) {
+	if int(uint32(len(items))) != len(items) {
+		return nil, errors.New("too many probabilities")
+	}
+
+	var bounds []time.Time
+	for _, it := range items {
+		if !(it.Weight > 0) {
+			return nil, errors.New("a probability is non-positive")
+		}
+		if !it.Start.IsZero() && !it.End.IsZero() && !it.End.After(it.Start) {
+			return nil, errors.New("a window ends before it starts")
+		}
+		if !it.Start.IsZero() {
+			bounds = append(bounds, it.Start)
+		}
+		if !it.End.IsZero() {
+			bounds = append(bounds, it.End)
+		}
+	}
+
+	sort.Slice(bounds, func(a, b int) bool { return bounds[a].Before(bounds[b]) })
+	uniq := bounds[:0]
+	for _, b := range bounds {
+		if len(uniq) == 0 || !b.Equal(uniq[len(uniq)-1]) {
+			uniq = append(uniq, b)
+		}
+	}
+
+	return &Schedule{
+		items:  append([]ScheduleItem(nil), items...),
+		bounds: uniq,
+		segs:   make([]segment, len(uniq)+1),
+	}, nil
+}
+
+// Gen picks an item active at now, according to the weights of the items
+// active then. It returns false if no item is active.
+func (s *Schedule) Gen(now time.Time, rng *rand.Rand) (uint32, bool) {
+	k := sort.Search(len(s.bounds), func(j int) bool {
+		return s.bounds[j].After(now)
+	})
+
+	seg := &s.segs[k]
+	seg.once.Do(func() { s.build(k) })
+
+	if seg.al == nil {
+		return 0, false
+	}
+	return seg.idx[seg.al.Gen(rng)], true
+}
+
+// build builds segment k's table.
+func (s *Schedule) build(k int) {
+	var idx []uint32
+	var prob []float64
+	for i, it := range s.items {
+		if s.active(it, k) {
+			idx = append(idx, uint32(i))
+			prob = append(prob, it.Weight)
+		}
+	}
+	if len(prob) == 0 {
+		return
+	}
+
+	// the weights were checked by NewSchedule, so this can't fail
+	al, err := New(prob)
+	if err != nil {
+		panic(err)
+	}
+
+	s.segs[k].al = al
+	s.segs[k].idx = idx
+}
+
+// active reports whether it is active throughout segment k.
+func (s *Schedule) active(it ScheduleItem, k int) bool {
+	if k == 0 {
+		// every start time is at or after the end of the first segment
+		return it.Start.IsZero()
+	}
+
+	t := s.bounds[k-1]
+	return (it.Start.IsZero() || !it.Start.After(t)) &&
+		(it.End.IsZero() || it.End.After(t))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2015, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	s, err := NewSchedule([]ScheduleItem{
+		{Weight: 1},
+		{Weight: 2, Start: day(2), End: day(4)},
+		{Weight: 3, Start: day(3)},
+		{Weight: 4, End: day(3)},
+	})
+	if err != nil {
+		t.Fatalf("Couldn't create Schedule: %v", err)
+	}
+
+	at := func(now time.Time) func(*rand.Rand) uint32 {
+		return func(rng *rand.Rand) uint32 {
+			i, ok := s.Gen(now, rng)
+			if !ok {
+				t.Fatalf("Gen(%v) failed", now)
+			}
+			return i
+		}
+	}
+
+	checkDistribution(t, at(day(1)), []float64{1, 0, 0, 4}, 1)
+	checkDistribution(t, at(day(2)), []float64{1, 2, 0, 4}, 2)
+	checkDistribution(t, at(day(3).Add(time.Hour)), []float64{1, 2, 3, 0}, 3)
+	checkDistribution(t, at(day(4)), []float64{1, 0, 3, 0}, 4)
+
+	// nothing active
+	s, err = NewSchedule([]ScheduleItem{{Weight: 1, Start: day(2), End: day(3)}})
+	if err != nil {
+		t.Fatalf("Couldn't create Schedule: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, now := range []time.Time{day(1), day(3), day(5)} {
+		if i, ok := s.Gen(now, rng); ok {
+			t.Errorf("Gen(%v) returned %v with nothing active", now, i)
+		}
+	}
+
+	bad := [][]ScheduleItem{
+		{{Weight: 0}},
+		{{Weight: 1, Start: day(2), End: day(2)}},
+	}
+	for _, items := range bad {
+		if _, err := NewSchedule(items); err == nil {
+			t.Errorf("NewSchedule(%v) did not fail", items)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+// weylStep is 2^64 divided by the golden ratio, rounded to odd. Stepping by
+// it visits points spread as evenly as any additive sequence can.
+const weylStep = 0x9E3779B97F4A7C15
+
+// Sequence produces a deterministic low-discrepancy sequence of draws: it
+// advances a Weyl sequence (multiples of the golden ratio, modulo 1) and
+// maps each point through Quantile. Any stretch of the sequence covers the
+// distribution much more evenly than independent draws would, which suits
+// generating test data without clumps. The draws are not random, and
+// consecutive draws are strongly related.
+type Sequence struct {
+	al *Alias
+	x  uint64
+}
+
+// NewSequence returns a Sequence over al. Different seeds give different
+// sequences.
+func NewSequence(al *Alias, seed uint64) *Sequence {
+	return &Sequence{al: al, x: seed}
+}
+
+// Next returns the next draw in the sequence.
+func (s *Sequence) Next() uint32 {
+	s.x += weylStep
+	return s.al.Quantile(float64(s.x>>11) / (1 << 53))
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSequence(t *testing.T) {
+	dist := []float64{5, 1, 3, 1}
+	a := mustNew(t, dist)
+	s := NewSequence(a, 1)
+
+	// after every stretch of 100 draws, counts are within a couple of the
+	// ideal, far tighter than independent draws would be
+	counts := make([]float64, len(dist))
+	for n := 1; n <= 10000; n++ {
+		counts[s.Next()]++
+		if n%100 != 0 {
+			continue
+		}
+		for i, w := range dist {
+			if math.Abs(counts[i]-float64(n)*w/10) > 2 {
+				t.Fatalf("After %v draws, index %v was drawn %v times", n, i, counts[i])
+			}
+		}
+	}
+}
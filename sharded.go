@@ -0,0 +1,106 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Sharded is a two-level sampler over a catalog split into shards: the top
+// level picks a shard by a weight that can be changed at any time, such as
+// the shard's live capacity, and the shard's own static table picks an item
+// within it.
+//
+// Changing a shard's weight costs O(shards), and each draw costs
+// O(log shards) on top of a draw from the shard's table.
+//
+// A Sharded is safe for concurrent use.
+type Sharded struct {
+	mu      sync.RWMutex
+	shards  []*Alias
+	weights []float64
+	cum     []float64 // running sums of weights
+}
+
+// NewSharded returns a Sharded over shards, with shard i initially given
+// weights[i]. Weights must be non-negative; a shard with weight zero is
+// never picked.
+func NewSharded(shards []*Alias, weights []float64) (*Sharded, error) {
+	if len(shards) != len(weights) {
+		return nil, errors.New("wrong number of weights")
+	}
+	for _, w := range weights {
+		if err := checkShardWeight(w); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Sharded{
+		shards:  append([]*Alias(nil), shards...),
+		weights: append([]float64(nil), weights...),
+		cum:     make([]float64, len(weights)),
+	}
+	s.resum()
+	return s, nil
+}
+
+func checkShardWeight(w float64) error {
+	if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+		return errors.New("a shard weight is negative or not finite")
+	}
+	return nil
+}
+
+func (s *Sharded) resum() {
+	total := float64(0)
+	for i, w := range s.weights {
+		total += w
+		s.cum[i] = total
+	}
+}
+
+// SetWeight changes the weight of shard i.
+func (s *Sharded) SetWeight(i int, w float64) error {
+	if err := checkShardWeight(w); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i < 0 || i >= len(s.weights) {
+		return errors.New("shard index out of range")
+	}
+
+	s.weights[i] = w
+
+	// resum rather than adjusting, so floating point error can't accumulate
+	// over many updates
+	s.resum()
+
+	return nil
+}
+
+// Gen picks a shard according to the shard weights, and then an item from
+// that shard's table. It returns false if every shard has weight zero.
+func (s *Sharded) Gen(rng *rand.Rand) (shard int, i uint32, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.cum) == 0 || s.cum[len(s.cum)-1] <= 0 {
+		return 0, 0, false
+	}
+
+	x := rng.Float64() * s.cum[len(s.cum)-1]
+	shard = sort.Search(len(s.cum)-1, func(j int) bool {
+		return s.cum[j] > x
+	})
+
+	return shard, s.shards[shard].Gen(rng), true
+}
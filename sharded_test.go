@@ -0,0 +1,53 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSharded(t *testing.T) {
+	a := mustNew(t, []float64{1, 3})
+	b := mustNew(t, []float64{1, 1})
+
+	s, err := NewSharded([]*Alias{a, b}, []float64{1, 3})
+	if err != nil {
+		t.Fatalf("Couldn't create Sharded: %v", err)
+	}
+
+	// flatten (shard, index) to shard*2 + index
+	gen := func(rng *rand.Rand) uint32 {
+		shard, i, ok := s.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed with live shards")
+		}
+		return uint32(shard)*2 + i
+	}
+
+	checkDistribution(t, gen, []float64{1, 3, 6, 6}, 1)
+
+	if err := s.SetWeight(1, 0); err != nil {
+		t.Fatalf("Couldn't SetWeight: %v", err)
+	}
+	checkDistribution(t, gen, []float64{1, 3, 0, 0}, 2)
+
+	if err := s.SetWeight(0, 0); err != nil {
+		t.Fatalf("Couldn't SetWeight: %v", err)
+	}
+	if _, _, ok := s.Gen(rand.New(rand.NewSource(1))); ok {
+		t.Errorf("Gen succeeded with every shard at weight zero")
+	}
+
+	if err := s.SetWeight(2, 1); err == nil {
+		t.Errorf("SetWeight out of range did not fail")
+	}
+	if err := s.SetWeight(0, -1); err == nil {
+		t.Errorf("SetWeight with a negative weight did not fail")
+	}
+	if _, err := NewSharded([]*Alias{a}, nil); err == nil {
+		t.Errorf("NewSharded with too few weights did not fail")
+	}
+}
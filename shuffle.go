@@ -0,0 +1,77 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// ShuffleSlice reorders items in place into a weighted random order:
+// weights[i] is the weight of items[i], the first item is drawn according
+// to the weights, the second according to the weights of the rest, and so
+// on, as by successive draws of a Draw session. Heavier items tend to come
+// earlier. Items with zero weight go last, in their original order.
+//
+// Weights must be non-negative with a positive sum. weights itself is left
+// as it is.
+func ShuffleSlice[T any](rng *rand.Rand, items []T, weights []float64) error {
+	if len(items) != len(weights) {
+		return errors.New("items and weights have different lengths")
+	}
+
+	al, _, err := build(weights, true)
+	if err != nil {
+		return err
+	}
+
+	// order[k] is the index of the item that goes in position k
+	order := make([]uint32, 0, len(items))
+	placed := make([]bool, len(items))
+	d := al.BeginDraw()
+	for i, w := range weights {
+		if w == 0 {
+			d.Exclude(uint32(i))
+		}
+	}
+	for {
+		i, ok := d.Gen(rng)
+		if !ok {
+			break
+		}
+		order = append(order, i)
+		placed[i] = true
+	}
+	for i := range items {
+		if !placed[i] {
+			order = append(order, uint32(i))
+		}
+	}
+
+	// apply the permutation by following its cycles, reusing placed to
+	// mark positions already filled
+	for i := range placed {
+		placed[i] = false
+	}
+	for start := range order {
+		if placed[start] {
+			continue
+		}
+		saved := items[start]
+		k := start
+		for {
+			placed[k] = true
+			src := int(order[k])
+			if src == start {
+				items[k] = saved
+				break
+			}
+			items[k] = items[src]
+			k = src
+		}
+	}
+
+	return nil
+}
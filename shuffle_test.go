@@ -0,0 +1,50 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestShuffleSlice(t *testing.T) {
+	weights := []float64{0, 5, 3, 0, 1, 1}
+
+	// the first item follows the weights
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		items := []uint32{0, 1, 2, 3, 4, 5}
+		if err := ShuffleSlice(rng, items, weights); err != nil {
+			t.Fatalf("Couldn't ShuffleSlice: %v", err)
+		}
+		return items[0]
+	}, weights, 1)
+
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		items := []string{"a", "b", "c", "d", "e", "f"}
+		if err := ShuffleSlice(rng, items, weights); err != nil {
+			t.Fatalf("Couldn't ShuffleSlice: %v", err)
+		}
+
+		// zero weights last, in order
+		if items[4] != "a" || items[5] != "d" {
+			t.Fatalf("ShuffleSlice gave %v", items)
+		}
+		sort.Strings(items)
+		for j, s := range items {
+			if s != string(rune('a'+j)) {
+				t.Fatalf("ShuffleSlice lost or duplicated items: %v", items)
+			}
+		}
+	}
+
+	if err := ShuffleSlice(rng, []int{1, 2}, []float64{1}); err == nil {
+		t.Errorf("ShuffleSlice with too few weights did not fail")
+	}
+	if err := ShuffleSlice(rng, []int{1}, []float64{0}); err == nil {
+		t.Errorf("ShuffleSlice with all weights zero did not fail")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+// WordSource is a source of uniformly random 64-bit words. It is satisfied
+// by math/rand/v2's Source interface and so by *rand.Rand, *rand.ChaCha8 and
+// *rand.PCG from that package, as well as by math/rand's Source64.
+type WordSource interface {
+	Uint64() uint64
+}
+
+// GenSource is Gen for math/rand/v2 and other sources of random words. It
+// takes exactly one word from src and uses its top 31 bits.
+//
+// The results are not the same as those of Gen on an rng with the same
+// source.
+func (al *Alias) GenSource(src WordSource) uint32 {
+	return al.genFrom(uint32(src.Uint64() >> 33))
+}
+
+// GenSource is Gen for math/rand/v2 and other sources of random words. It
+// takes exactly one word from src and uses its top 63 bits.
+func (wd *Wide) GenSource(src WordSource) uint32 {
+	return wd.genFrom(src.Uint64() >> 1)
+}
+
+// GenSource picks a random key as Gen does, using one word from src.
+func (k *Keyed) GenSource(src WordSource) string {
+	return k.keys[k.al.GenSource(src)]
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	randv2 "math/rand/v2"
+	"testing"
+)
+
+func TestGenSource(t *testing.T) {
+	dist := []float64{1, 5, 2, 0.5}
+	a := mustNew(t, dist)
+	wd, err := NewWide(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create wide: %v", err)
+	}
+
+	sources := []WordSource{
+		randv2.NewChaCha8([32]byte{2}),
+		randv2.NewPCG(1, 2),
+		randv2.New(randv2.NewPCG(3, 4)),
+		rand.NewSource(5).(rand.Source64),
+	}
+	for i, src := range sources {
+		checkDistribution(t, func(*rand.Rand) uint32 {
+			return a.GenSource(src)
+		}, dist, int64(i))
+		checkDistribution(t, func(*rand.Rand) uint32 {
+			return wd.GenSource(src)
+		}, dist, int64(i))
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"sync"
+	"time"
+)
+
+// TrafficSplitter assigns keys, such as user or session IDs, to weighted
+// arms, the way A/B tests and gradual rollouts do. Assignment is sticky: a
+// key gets the same arm as long as the weights don't change.
+//
+// Changing the weights ramps between the old and new weights over a time
+// window. During the ramp each key moves from its old assignment to its new
+// one at a fixed point in the window determined by its hash, so the traffic
+// split interpolates linearly between the two sets of weights, and each key
+// moves at most once.
+//
+// A TrafficSplitter is safe for concurrent use.
+type TrafficSplitter struct {
+	hash func([]byte) uint64
+
+	mu     sync.RWMutex
+	old    *Alias
+	cur    *Alias
+	start  time.Time
+	window time.Duration
+}
+
+// NewTrafficSplitter returns a TrafficSplitter with the given weights, which
+// must be positive as with New. Keys are hashed with hash, or with 64-bit
+// FNV-1a if hash is nil, as with GenKey.
+func NewTrafficSplitter(weights []float64, hash func([]byte) uint64) (*TrafficSplitter, error) {
+	al, err := New(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	if hash == nil {
+		hash = fnv1a
+	}
+	return &TrafficSplitter{hash: hash, old: al, cur: al}, nil
+}
+
+// Update starts ramping to new weights at now, finishing after window. The
+// weights may have a different number of arms than before. If a ramp is
+// still in progress, it's cut short and the new ramp starts from the weights
+// it was heading to.
+func (ts *TrafficSplitter) Update(weights []float64, now time.Time, window time.Duration) error {
+	al, err := New(weights)
+	if err != nil {
+		return err
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.old = ts.cur
+	ts.cur = al
+	ts.start = now
+	ts.window = window
+	return nil
+}
+
+// Assign returns the arm for key at time now.
+func (ts *TrafficSplitter) Assign(key []byte, now time.Time) uint32 {
+	h := ts.hash(key)
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	// GenHash only looks at the top 31 bits, so the bottom 32 are free to
+	// pick the point in the ramp at which this key switches over
+	if ts.progress(now) <= float64(uint32(h))/(1<<32) {
+		return ts.old.GenHash(h)
+	}
+	return ts.cur.GenHash(h)
+}
+
+// progress returns how far through the ramp now is, from 0 to 1.
+func (ts *TrafficSplitter) progress(now time.Time) float64 {
+	elapsed := now.Sub(ts.start)
+	if elapsed >= ts.window {
+		return 1
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(elapsed) / float64(ts.window)
+}
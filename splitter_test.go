@@ -0,0 +1,56 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestTrafficSplitter(t *testing.T) {
+	ts, err := NewTrafficSplitter([]float64{1, 0.5, 0.5}, nil)
+	if err != nil {
+		t.Fatalf("Couldn't create splitter: %v", err)
+	}
+
+	start := time.Unix(1000, 0)
+	key := make([]byte, 8)
+	assign := func(now time.Time) func(*rand.Rand) uint32 {
+		return func(rng *rand.Rand) uint32 {
+			binary.LittleEndian.PutUint64(key, rng.Uint64())
+			return ts.Assign(key, now)
+		}
+	}
+
+	checkDistribution(t, assign(start), []float64{2, 1, 1}, 1)
+
+	if err := ts.Update([]float64{0.5, 0.5, 1}, start, time.Hour); err != nil {
+		t.Fatalf("Couldn't update: %v", err)
+	}
+
+	checkDistribution(t, assign(start), []float64{2, 1, 1}, 2)
+	checkDistribution(t, assign(start.Add(30*time.Minute)), []float64{3, 2, 3}, 3)
+	checkDistribution(t, assign(start.Add(2*time.Hour)), []float64{1, 1, 2}, 4)
+
+	// keys move at most once, from their old arm to their new one
+	rng := rand.New(rand.NewSource(5))
+	for n := 0; n < 1000; n++ {
+		binary.LittleEndian.PutUint64(key, rng.Uint64())
+		first := ts.Assign(key, start)
+		last := ts.Assign(key, start.Add(time.Hour))
+		moved := false
+		for m := time.Duration(0); m <= 60; m++ {
+			a := ts.Assign(key, start.Add(m*time.Minute))
+			if a != first {
+				moved = true
+			}
+			if moved && a != last {
+				t.Fatalf("Key %x went %v, %v, and back to %v", key, first, last, a)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"unsafe"
+)
+
+// FromStatic returns a table using t as its storage, where t holds each
+// slot's threshold followed by its alias target, as in the interleaved
+// Layout. This is the constructor used by code written by WriteGo: t is
+// checked but not copied or decoded, so loading a table compiled into a
+// program only allocates the small *Alias wrapping it. t must not be
+// modified afterwards.
+func FromStatic(t []uint32) (*Alias, error) {
+	if len(t) == 0 || len(t)%2 != 0 {
+		return nil, errors.New("bad data length")
+	}
+
+	n := len(t) / 2
+	if int(uint32(n)) != n {
+		return nil, errors.New("data too large")
+	}
+
+	for i := 0; i < len(t); i += 2 {
+		if t[i] >= 1<<31 {
+			return nil, errors.New("bad data: probability out of range")
+		}
+		if t[i+1] >= uint32(n) {
+			return nil, errors.New("bad data: alias target out of range")
+		}
+	}
+
+	// ipiece is exactly a threshold and an alias target
+	table := unsafe.Slice((*ipiece)(unsafe.Pointer(&t[0])), n)
+	return &Alias{table: table}, nil
+}
+
+// WriteGo writes a Go source file for package pkg holding the table as an
+// array literal, along with a function called name returning it as an
+// *Alias, built on first call with FromStatic. Calling WriteGo from a
+// program run by go:generate lets a static distribution ship inside the
+// binary, with no file to read and nothing to parse at startup.
+func (al *Alias) WriteGo(w io.Writer, pkg, name string) error {
+	if !token.IsIdentifier(pkg) || !token.IsIdentifier(name) {
+		return errors.New("bad package or function name")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by alias.WriteGo. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n\"sync\"\n\n\"github.com/encryptio/alias\"\n)\n\n")
+
+	fmt.Fprintf(&buf, "var aliasTable%s = [...]uint32{\n", name)
+	for _, piece := range al.table {
+		fmt.Fprintf(&buf, "%d, %d,\n", piece.prob, piece.alias)
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "var (\naliasOnce%s sync.Once\nalias%s *alias.Alias\n)\n\n", name, name)
+	fmt.Fprintf(&buf, "// %s returns the table generated by alias.WriteGo.\n", name)
+	fmt.Fprintf(&buf, "func %s() *alias.Alias {\n", name)
+	fmt.Fprintf(&buf, "aliasOnce%s.Do(func() {\n", name)
+	fmt.Fprintf(&buf, "var err error\n")
+	fmt.Fprintf(&buf, "alias%s, err = alias.FromStatic(aliasTable%s[:])\n", name, name)
+	fmt.Fprintf(&buf, "if err != nil {\npanic(err)\n}\n")
+	fmt.Fprintf(&buf, "})\nreturn alias%s\n}\n", name)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
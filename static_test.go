@@ -0,0 +1,66 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromStatic(t *testing.T) {
+	a := mustNew(t, []float64{10, 1, 2, 3})
+
+	var static []uint32
+	for _, piece := range a.table {
+		static = append(static, piece.prob, piece.alias)
+	}
+
+	s, err := FromStatic(static)
+	if err != nil {
+		t.Fatalf("Couldn't FromStatic: %v", err)
+	}
+	if !reflect.DeepEqual(s.table, a.table) {
+		t.Errorf("FromStatic table was %v, wanted %v", s.table, a.table)
+	}
+	checkDistribution(t, s.Gen, []float64{10, 1, 2, 3}, 1)
+
+	for _, bad := range [][]uint32{
+		nil,
+		{0},
+		{1 << 31, 0},
+		{0, 1},
+	} {
+		if _, err := FromStatic(bad); err == nil {
+			t.Errorf("FromStatic(%v) did not fail", bad)
+		}
+	}
+}
+
+func TestWriteGo(t *testing.T) {
+	a := mustNew(t, []float64{1, 3})
+
+	var buf bytes.Buffer
+	if err := a.WriteGo(&buf, "weather", "Forecast"); err != nil {
+		t.Fatalf("Couldn't WriteGo: %v", err)
+	}
+
+	src := buf.String()
+	for _, want := range []string{
+		"// Code generated by alias.WriteGo. DO NOT EDIT.\n",
+		"package weather\n",
+		"\t1073741823, 1,\n\t2147483647, 0,\n",
+		"func Forecast() *alias.Alias {\n",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("WriteGo output lacks %q:\n%s", want, src)
+		}
+	}
+
+	if err := a.WriteGo(&buf, "weather", "not a name"); err == nil {
+		t.Errorf("WriteGo with a bad name did not fail")
+	}
+}
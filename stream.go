@@ -0,0 +1,180 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// streamChunk is the number of slots WriteTo and ReadFrom buffer at a time.
+const streamChunk = 4096
+
+// WriteTo implements io.WriterTo, writing the same bytes as MarshalBinary a
+// chunk at a time, so a large table can be written out without a second
+// copy of it in memory.
+func (al *Alias) WriteTo(w io.Writer) (int64, error) {
+	crc := crc32.NewIEEE()
+	total := int64(0)
+	write := func(b []byte) error {
+		crc.Write(b)
+		n, err := w.Write(b)
+		total += int64(n)
+		return err
+	}
+
+	buf := appendHeader(make([]byte, 0, 8*streamChunk), len(al.table))
+	if err := write(buf); err != nil {
+		return total, err
+	}
+
+	for start := 0; start < len(al.table); start += streamChunk {
+		end := start + streamChunk
+		if end > len(al.table) {
+			end = len(al.table)
+		}
+
+		buf = buf[:0]
+		for _, piece := range al.table[start:end] {
+			buf = binary.LittleEndian.AppendUint32(buf, piece.prob)
+			buf = binary.LittleEndian.AppendUint32(buf, piece.alias)
+		}
+		if err := write(buf); err != nil {
+			return total, err
+		}
+	}
+
+	n, err := w.Write(binary.LittleEndian.AppendUint32(buf[:0], crc.Sum32()))
+	total += int64(n)
+	return total, err
+}
+
+// ReadFrom implements io.ReaderFrom, reading a table written by WriteTo or
+// MarshalBinary from r a chunk at a time, so a large table can be read
+// without holding its encoding in memory. It stops after the checksum, and
+// so may be used on a stream holding more data.
+//
+// Data in the headerless v1 format is also accepted, and read until EOF.
+func (al *Alias) ReadFrom(r io.Reader) (int64, error) {
+	head := make([]byte, formatHeader)
+	n, err := io.ReadFull(r, head[:4])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return int64(n), err
+	}
+	if !isV2(head[:n]) {
+		total, err := al.readV1(io.MultiReader(bytes.NewReader(head[:n]), r))
+		return total, err
+	}
+
+	m, err := io.ReadFull(r, head[4:])
+	total := int64(n + m)
+	if err != nil {
+		return total, noEOF(err)
+	}
+	count, err := parseHeader(head)
+	if err != nil {
+		return total, err
+	}
+
+	crc := crc32.NewIEEE()
+	crc.Write(head)
+
+	table := make([]ipiece, 0, min(count, streamChunk))
+	buf := make([]byte, 8*streamChunk)
+	for len(table) < count {
+		chunk := buf[:8*min(count-len(table), streamChunk)]
+		n, err := io.ReadFull(r, chunk)
+		total += int64(n)
+		if err != nil {
+			return total, noEOF(err)
+		}
+		crc.Write(chunk)
+
+		if table, err = appendSlots(table, chunk); err != nil {
+			return total, err
+		}
+	}
+
+	n, err = io.ReadFull(r, buf[:formatFooter])
+	total += int64(n)
+	if err != nil {
+		return total, noEOF(err)
+	}
+	if crc.Sum32() != binary.LittleEndian.Uint32(buf) {
+		return total, errors.New("bad data: checksum mismatch")
+	}
+
+	return total, al.setStreamed(table)
+}
+
+// readV1 reads a table in the v1 format from r until EOF.
+func (al *Alias) readV1(r io.Reader) (int64, error) {
+	buf := make([]byte, 8*streamChunk)
+	total := int64(0)
+	var table []ipiece
+	for {
+		n, err := io.ReadFull(r, buf)
+		total += int64(n)
+		if n%8 != 0 {
+			return total, errors.New("bad data length")
+		}
+
+		var perr error
+		if table, perr = appendSlots(table, buf[:n]); perr != nil {
+			return total, perr
+		}
+		if int(uint32(len(table))) != len(table) {
+			return total, errors.New("data too large")
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, al.setStreamed(table)
+}
+
+// appendSlots decodes the slots in p, in the zero Layout, and appends them
+// to table.
+func appendSlots(table []ipiece, p []byte) ([]ipiece, error) {
+	for ; len(p) > 0; p = p[8:] {
+		prob := binary.LittleEndian.Uint32(p)
+		if prob >= 1<<31 {
+			return table, errors.New("bad data: probability out of range")
+		}
+		table = append(table, ipiece{prob, binary.LittleEndian.Uint32(p[4:])})
+	}
+	return table, nil
+}
+
+// setStreamed checks the alias targets of a table read by ReadFrom, which
+// can't be checked until the table's size is known, and replaces al's table
+// with it.
+func (al *Alias) setStreamed(table []ipiece) error {
+	for _, piece := range table {
+		if piece.alias >= uint32(len(table)) {
+			return errors.New("bad data: alias target out of range")
+		}
+	}
+
+	// reset everything derived from the old table
+	*al = Alias{table: table}
+	return nil
+}
+
+// noEOF turns an EOF in the middle of a table into an error saying so.
+func noEOF(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("bad data length")
+	}
+	return err
+}
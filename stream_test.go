@@ -0,0 +1,98 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWriteToReadFrom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 3, streamChunk, 3*streamChunk + 17} {
+		weights := make([]float64, n)
+		for i := range weights {
+			weights[i] = rng.Float64() + 0.01
+		}
+		a := mustNew(t, weights)
+
+		var buf bytes.Buffer
+		written, err := a.WriteTo(&buf)
+		if err != nil {
+			t.Fatalf("Couldn't WriteTo: %v", err)
+		}
+		data, _ := a.MarshalBinary()
+		if written != int64(len(data)) || !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("WriteTo didn't write the MarshalBinary encoding")
+		}
+
+		// read back in small pieces
+		a2 := &Alias{}
+		read, err := a2.ReadFrom(iotest.HalfReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatalf("Couldn't ReadFrom: %v", err)
+		}
+		if read != written {
+			t.Errorf("ReadFrom read %v bytes, wanted %v", read, written)
+		}
+		if !reflect.DeepEqual(a, a2) {
+			t.Fatalf("Read version was not the same as original")
+		}
+	}
+}
+
+func TestReadFromV1(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 3})
+	a2 := &Alias{}
+	if _, err := a2.ReadFrom(bytes.NewReader(a.MarshalLayout(Layout{}))); err != nil {
+		t.Fatalf("Couldn't ReadFrom v1 data: %v", err)
+	}
+	if !reflect.DeepEqual(a, a2) {
+		t.Fatalf("Read version was not the same as original")
+	}
+}
+
+func TestReadFromTrailing(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 3})
+	data, _ := a.MarshalBinary()
+
+	// the rest of the stream is left for the caller
+	r := bytes.NewReader(append(data[:len(data):len(data)], "rest"...))
+	if _, err := (&Alias{}).ReadFrom(r); err != nil {
+		t.Fatalf("Couldn't ReadFrom: %v", err)
+	}
+	if r.Len() != 4 {
+		t.Errorf("ReadFrom left %v bytes, wanted 4", r.Len())
+	}
+}
+
+func TestReadFromErrors(t *testing.T) {
+	data, _ := mustNew(t, []float64{1, 2, 3}).MarshalBinary()
+	v1 := mustNew(t, []float64{1, 2, 3}).MarshalLayout(Layout{})
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[formatHeader+1] ^= 1
+
+	bad := [][]byte{
+		data[:len(data)-1],
+		data[:6],
+		corrupt,
+		v1[:len(v1)-1],
+		{0, 0, 0, 0, 5, 0, 0, 0},    // alias target out of range
+		{0, 0, 0, 0x80, 0, 0, 0, 0}, // not v2, and probability out of range
+	}
+	for _, p := range bad {
+		if _, err := (&Alias{}).ReadFrom(bytes.NewReader(p)); err == nil {
+			t.Errorf("ReadFrom(%v) did not fail", p)
+		}
+	}
+
+	if _, err := (&Alias{}).ReadFrom(iotest.ErrReader(iotest.ErrTimeout)); err != iotest.ErrTimeout {
+		t.Errorf("ReadFrom didn't return the reader's error, got %v", err)
+	}
+}
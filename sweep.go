@@ -0,0 +1,79 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+// sweep pairs off the items of prob like vose, but without vose's 16 bytes
+// of scratch memory per item, so a table can be built using no memory but
+// the table itself.
+//
+// Instead of stacks of small and large items, it sweeps two cursors through
+// prob in index order, one stopping at items below the average and one at
+// items above it. Each small item is given to the current large item, whose
+// remaining probability is the only one that needs to be kept; once that
+// falls below the average, the large item is itself treated as small and
+// given to the next large item. Every other probability is read from prob
+// again as needed, and slots are only ever written, never read, so set may
+// write straight into the table being built.
+//
+// It gives a different, equally valid table than vose for the same prob.
+func sweep(prob []float64, total float64, set func(i uint32, p float64, alias uint32)) float64 {
+	n := len(prob)
+	mult := float64(n) / total
+
+	nextSmall := func(i int) int {
+		for i < n && prob[i]*mult >= 1 {
+			i++
+		}
+		return i
+	}
+	nextLarge := func(i int) int {
+		for i < n && prob[i]*mult < 1 {
+			i++
+		}
+		return i
+	}
+
+	leftover := float64(0)
+
+	g := nextLarge(0)
+	if g == n {
+		// floating point error left every item just under the average
+		for i, p := range prob {
+			leftover += 1 - p*mult
+			set(uint32(i), 1, 0)
+		}
+		return leftover / float64(n)
+	}
+	r := prob[g] * mult
+
+	for s := nextSmall(0); s < n; s = nextSmall(s + 1) {
+		p := prob[s] * mult
+		set(uint32(s), p, uint32(g))
+		r = (r + p) - 1
+
+		// the large item has become small; give it to the next large one
+		for r < 1 {
+			next := nextLarge(g + 1)
+			if next == n {
+				break
+			}
+			set(uint32(g), r, uint32(next))
+			r = (prob[next]*mult + r) - 1
+			g = next
+		}
+	}
+
+	// there should be no small items left, but floating point errors can
+	// leave the last large item just under 1
+	if r < 1 {
+		leftover += 1 - r
+	}
+	set(uint32(g), 1, 0)
+	for i := nextLarge(g + 1); i < n; i = nextLarge(i + 1) {
+		set(uint32(i), 1, 0)
+	}
+
+	return leftover / float64(n)
+}
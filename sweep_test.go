@@ -0,0 +1,47 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSweep(t *testing.T) {
+	for _, prob := range [][]float64{
+		{1},
+		{1, 1, 1},
+		{5, 1, 1, 1},
+		{1, 1, 1, 5},
+		{0, 3, 0, 1, 4},
+		{1e-9, 1, 1e-9, 2, 1e-9},
+		{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9},
+	} {
+		want, _, err := buildWith(prob, true, vose)
+		if err != nil {
+			t.Fatalf("Couldn't build %v: %v", prob, err)
+		}
+		got, _, err := buildWith(prob, true, sweep)
+		if err != nil {
+			t.Fatalf("Couldn't sweep %v: %v", prob, err)
+		}
+		for i := range prob {
+			if p, w := got.Prob(uint32(i)), want.Prob(uint32(i)); math.Abs(p-w) > 1e-8 {
+				t.Errorf("Sweep of %v gave Prob(%v) %v, wanted %v", prob, i, p, w)
+			}
+		}
+
+		// Wide tables don't take zero weights
+		wd, _, err := buildWide(prob, sweep)
+		if err != nil {
+			continue
+		}
+		for i := range prob {
+			if p, w := wd.Prob(uint32(i)), want.Prob(uint32(i)); math.Abs(p-w) > 1e-8 {
+				t.Errorf("Wide sweep of %v gave Prob(%v) %v, wanted %v", prob, i, p, w)
+			}
+		}
+	}
+}
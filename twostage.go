@@ -0,0 +1,79 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import "errors"
+
+// TwoStage picks items assigned to groups in two stages, first a group and
+// then an item within it, with the same probabilities as NewGrouped. Unlike
+// a NewGrouped table, each stage takes its randomness from its own source,
+// which makes for hybrid policies: hashing a user ID for the first stage
+// and using an rng for the second, say, sticks each user to one group but
+// varies the item within it.
+//
+// A *math/rand.Rand and anything from math/rand/v2 can be a source; to use
+// a hash value, convert it to a Word.
+type TwoStage struct {
+	groups  *Alias
+	members [][]uint32 // the items of each group
+	items   []*Alias   // each group's table over its members
+}
+
+// Word is a WordSource that always returns the same word, such as a hash
+// value.
+type Word uint64
+
+// Uint64 returns w.
+func (w Word) Uint64() uint64 {
+	return uint64(w)
+}
+
+// NewTwoStage returns a TwoStage over items assigned to groups, with the
+// arguments as for NewGrouped.
+func NewTwoStage(groups []uint32, itemWeights, groupWeights []float64) (*TwoStage, error) {
+	if len(groups) != len(itemWeights) {
+		return nil, errors.New("groups and item weights have different lengths")
+	}
+
+	ts := &TwoStage{members: make([][]uint32, len(groupWeights))}
+	for i, g := range groups {
+		if int(g) >= len(groupWeights) {
+			return nil, errors.New("group out of range")
+		}
+		ts.members[g] = append(ts.members[g], uint32(i))
+	}
+
+	var err error
+	ts.groups, err = New(groupWeights)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.items = make([]*Alias, len(groupWeights))
+	for g, members := range ts.members {
+		if len(members) == 0 {
+			return nil, errors.New("a group has no items")
+		}
+		weights := make([]float64, len(members))
+		for j, i := range members {
+			weights[j] = itemWeights[i]
+		}
+		if ts.items[g], err = New(weights); err != nil {
+			return nil, err
+		}
+	}
+
+	return ts, nil
+}
+
+// Gen picks a group using one word from stage1, then an item of that group
+// using one word from stage2, and returns both. Passing the same source for
+// both stages is fine, but passing the same Word for both is not: the two
+// stages would no longer be independent.
+func (ts *TwoStage) Gen(stage1, stage2 WordSource) (group, item uint32) {
+	group = ts.groups.GenSource(stage1)
+	item = ts.members[group][ts.items[group].GenSource(stage2)]
+	return group, item
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTwoStage(t *testing.T) {
+	groups := []uint32{0, 1, 0, 1, 2}
+	itemWeights := []float64{1, 1, 3, 2, 1}
+	groupWeights := []float64{4, 3, 1}
+	ts, err := NewTwoStage(groups, itemWeights, groupWeights)
+	if err != nil {
+		t.Fatalf("Couldn't create TwoStage: %v", err)
+	}
+
+	// the same distribution as NewGrouped
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		g, i := ts.Gen(rng, rng)
+		if groups[i] != g {
+			t.Fatalf("Item %v is not in group %v", i, g)
+		}
+		return i
+	}, []float64{1, 1, 3, 2, 1}, 1)
+
+	// a fixed first stage always gives the same group
+	sticky := Word(fnv1a([]byte("user 17")))
+	rng := rand.New(rand.NewSource(2))
+	want, _ := ts.Gen(sticky, rng)
+	for i := 0; i < 100; i++ {
+		if g, _ := ts.Gen(sticky, rng); g != want {
+			t.Fatalf("Group changed from %v to %v with the same first stage", want, g)
+		}
+	}
+
+	if _, err := NewTwoStage([]uint32{0, 0}, []float64{1, 1}, []float64{1, 1}); err == nil {
+		t.Errorf("NewTwoStage with an empty group did not fail")
+	}
+	if _, err := NewTwoStage([]uint32{0, 2}, []float64{1, 1}, []float64{1, 1}); err == nil {
+		t.Errorf("NewTwoStage with a group out of range did not fail")
+	}
+	if _, err := NewTwoStage([]uint32{0, 1}, []float64{1, 0}, []float64{1, 1}); err == nil {
+		t.Errorf("NewTwoStage with a zero item weight did not fail")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+// Verification is the exact comparison of a table with the weights it was
+// built from, made by VerifyExact.
+type Verification struct {
+	// Prob is the exact probability the table gives each index.
+	Prob []*big.Rat
+
+	// Want is the exact probability each index was meant to have: its
+	// weight over the exact sum of the weights.
+	Want []*big.Rat
+
+	// Error is Prob minus Want for each index.
+	Error []*big.Rat
+
+	// MaxError is the largest absolute value in Error, and MaxIndex the
+	// first index with it.
+	MaxError *big.Rat
+	MaxIndex uint32
+}
+
+// VerifyExact recomputes the table's distribution in exact rational
+// arithmetic and compares it with weights, which should be those the table
+// was built from. It involves no floating point rounding at all, so it
+// suits one-off certification of tables used for prize draws and the like,
+// where the exact error must be reported. It is much slower than Prob.
+func (al *Alias) VerifyExact(weights []float64) (*Verification, error) {
+	n := len(al.table)
+	if len(weights) != n {
+		return nil, errors.New("wrong number of weights")
+	}
+
+	total := new(big.Rat)
+	want := make([]*big.Rat, n)
+	for i, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, errors.New("a probability is negative or not finite")
+		}
+		want[i] = new(big.Rat).SetFloat64(w)
+		total.Add(total, want[i])
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("probabilities sum to zero")
+	}
+
+	counts := tableCounts(al.table)
+
+	v := &Verification{
+		Prob:     make([]*big.Rat, n),
+		Want:     want,
+		Error:    make([]*big.Rat, n),
+		MaxError: new(big.Rat),
+	}
+
+	denom := new(big.Int).Lsh(big.NewInt(1), 31)
+	for i := range want {
+		want[i].Quo(want[i], total)
+		v.Prob[i] = new(big.Rat).SetFrac(new(big.Int).SetUint64(counts[i]), denom)
+		v.Error[i] = new(big.Rat).Sub(v.Prob[i], want[i])
+
+		abs := new(big.Rat).Abs(v.Error[i])
+		if abs.Cmp(v.MaxError) > 0 {
+			v.MaxError = abs
+			v.MaxIndex = uint32(i)
+		}
+	}
+
+	return v, nil
+}
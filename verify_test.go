@@ -0,0 +1,55 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestVerifyExact(t *testing.T) {
+	// quarters are represented exactly
+	a := mustNew(t, []float64{1, 3})
+	v, err := a.VerifyExact([]float64{1, 3})
+	if err != nil {
+		t.Fatalf("Couldn't VerifyExact: %v", err)
+	}
+	if v.Prob[0].Cmp(big.NewRat(1, 4)) != 0 || v.Prob[1].Cmp(big.NewRat(3, 4)) != 0 {
+		t.Errorf("Prob was %v, wanted [1/4 3/4]", v.Prob)
+	}
+	if v.MaxError.Sign() != 0 {
+		t.Errorf("MaxError was %v, wanted 0", v.MaxError)
+	}
+
+	// thirds aren't, and the error matches Prob's
+	weights := []float64{1, 1, 1}
+	a = mustNew(t, weights)
+	v, err = a.VerifyExact(weights)
+	if err != nil {
+		t.Fatalf("Couldn't VerifyExact: %v", err)
+	}
+	sum := new(big.Rat)
+	for i := range weights {
+		sum.Add(sum, v.Prob[i])
+		if p, _ := v.Prob[i].Float64(); p != a.Prob(uint32(i)) {
+			t.Errorf("Prob[%v] was %v, Prob gives %v", i, p, a.Prob(uint32(i)))
+		}
+		e, _ := v.Error[i].Float64()
+		if want := a.Prob(uint32(i)) - 1.0/3; math.Abs(e-want) > 1e-15 {
+			t.Errorf("Error[%v] was %v, wanted about %v", i, e, want)
+		}
+	}
+	if sum.Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("Probabilities sum to %v", sum)
+	}
+	if abs := new(big.Rat).Abs(v.Error[v.MaxIndex]); abs.Cmp(v.MaxError) != 0 || v.MaxError.Sign() == 0 {
+		t.Errorf("MaxError was %v at %v", v.MaxError, v.MaxIndex)
+	}
+
+	if _, err := a.VerifyExact([]float64{1}); err == nil {
+		t.Errorf("VerifyExact with too few weights did not fail")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+)
+
+// GenWithVeto generates a random number that veto doesn't reject, according
+// to the distribution renormalized over the indices veto accepts. It returns
+// false if veto rejects every index with nonzero probability.
+//
+// It first tries drawing from Gen up to maxRetries times. If every draw is
+// vetoed, it falls back to an exact O(n) search, calling veto once for each
+// index with nonzero probability, so the cost of a draw is bounded however
+// much of the mass veto rejects. veto should give the same answer for an
+// index throughout the call.
+func (al *Alias) GenWithVeto(rng *rand.Rand, veto func(uint32) bool, maxRetries int) (uint32, bool) {
+	i, ok, _ := al.GenWithVetoContext(context.Background(), rng, veto, maxRetries)
+	return i, ok
+}
+
+// vetoCheckEvery is how many indices GenWithVetoContext's fallback search
+// goes through between checks of its context.
+const vetoCheckEvery = 1024
+
+// GenWithVetoContext is GenWithVeto, but gives up with ctx.Err() if ctx is
+// done before it finishes. ctx is checked before each retry, and
+// periodically during the fallback search, since veto may be slow.
+func (al *Alias) GenWithVetoContext(ctx context.Context, rng *rand.Rand, veto func(uint32) bool, maxRetries int) (uint32, bool, error) {
+	for try := 0; try < maxRetries; try++ {
+		if err := ctx.Err(); err != nil {
+			return 0, false, err
+		}
+		i := al.Gen(rng)
+		if !veto(i) {
+			return i, true, nil
+		}
+	}
+
+	pmf := al.pmf()
+	allowed := make([]bool, len(pmf))
+	found := false
+	for i, p := range pmf {
+		if i%vetoCheckEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, false, err
+			}
+		}
+		if p > 0 && !veto(uint32(i)) {
+			allowed[i] = true
+			found = true
+		}
+	}
+	if !found {
+		return 0, false, nil
+	}
+
+	return scan(rng, pmf, func(i int, p float64) float64 {
+		if !allowed[i] {
+			return 0
+		}
+		return p
+	}), true, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestGenWithVeto(t *testing.T) {
+	a := mustNew(t, []float64{100, 1, 2, 3})
+	notZero := func(i uint32) bool { return i == 0 }
+
+	for _, retries := range []int{0, 2, 1000} {
+		checkDistribution(t, func(rng *rand.Rand) uint32 {
+			i, ok := a.GenWithVeto(rng, notZero, retries)
+			if !ok {
+				t.Fatalf("GenWithVeto failed with indices allowed")
+			}
+			return i
+		}, []float64{0, 1, 2, 3}, int64(retries))
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	all := func(uint32) bool { return true }
+	if i, ok := a.GenWithVeto(rng, all, 10); ok {
+		t.Errorf("GenWithVeto returned %v with every index vetoed", i)
+	}
+}
+
+func TestGenWithVetoContext(t *testing.T) {
+	a := mustNew(t, []float64{1, 2, 3})
+	rng := rand.New(rand.NewSource(1))
+
+	i, ok, err := a.GenWithVetoContext(context.Background(), rng, func(i uint32) bool { return i != 2 }, 0)
+	if err != nil || !ok || i != 2 {
+		t.Errorf("GenWithVetoContext returned %v, %v, %v; wanted 2", i, ok, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, retries := range []int{0, 10} {
+		if _, _, err := a.GenWithVetoContext(ctx, rng, func(uint32) bool { return true }, retries); err != context.Canceled {
+			t.Errorf("GenWithVetoContext with %v retries after cancel returned %v", retries, err)
+		}
+	}
+}
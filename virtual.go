@@ -0,0 +1,120 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// VirtualIndex samples across a catalog federated from several sources,
+// each with its own item weights and local indices. Internally the sources'
+// items get contiguous indices in one table, source after source, so every
+// item is drawn in proportion to its weight among all the items, and Gen
+// translates back to the source and the item's index in it.
+//
+// The table is rebuilt on the first draw after a source is registered.
+//
+// A VirtualIndex is safe for concurrent use, provided each goroutine uses
+// its own rng.
+type VirtualIndex struct {
+	mu      sync.RWMutex
+	weights []float64 // every source's weights, in order
+	offsets []uint32  // internal index of each source's first item
+	al      *Alias    // nil if a source was registered since the last build
+}
+
+// NewVirtualIndex returns a VirtualIndex with no sources.
+func NewVirtualIndex() *VirtualIndex {
+	return &VirtualIndex{}
+}
+
+// Register adds a source whose items have the given weights, which must be
+// positive, and returns its number. Sources are numbered from zero in the
+// order they're registered.
+func (v *VirtualIndex) Register(weights []float64) (int, error) {
+	if _, err := checkProb(weights, false); err != nil {
+		return 0, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	n := len(v.weights) + len(weights)
+	if int(uint32(n)) != n {
+		return 0, errors.New("too many probabilities")
+	}
+
+	v.offsets = append(v.offsets, uint32(len(v.weights)))
+	v.weights = append(v.weights, weights...)
+	v.al = nil
+	return len(v.offsets) - 1, nil
+}
+
+// Gen draws an item from across all the sources, and returns its source and
+// its index within the source. It returns false if no source is registered.
+func (v *VirtualIndex) Gen(rng *rand.Rand) (source int, local uint32, ok bool) {
+	v.mu.RLock()
+	al := v.al
+	v.mu.RUnlock()
+
+	if al == nil {
+		var err error
+		if al, err = v.build(); err != nil {
+			return 0, 0, false
+		}
+	}
+
+	source, local = v.Locate(al.Gen(rng))
+	return source, local, true
+}
+
+// build builds the table if it's stale, and returns it.
+func (v *VirtualIndex) build() (*Alias, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.al == nil {
+		al, err := New(v.weights)
+		if err != nil {
+			return nil, err
+		}
+		v.al = al
+	}
+	return v.al, nil
+}
+
+// Locate translates internal index i to its source and index within the
+// source. i must be less than the total number of items.
+func (v *VirtualIndex) Locate(i uint32) (source int, local uint32) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	source = sort.Search(len(v.offsets), func(s int) bool {
+		return v.offsets[s] > i
+	}) - 1
+	return source, i - v.offsets[source]
+}
+
+// Index translates an item of a source to its internal index. It returns
+// false if there's no such item.
+func (v *VirtualIndex) Index(source int, local uint32) (uint32, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if source < 0 || source >= len(v.offsets) {
+		return 0, false
+	}
+	end := uint32(len(v.weights))
+	if source+1 < len(v.offsets) {
+		end = v.offsets[source+1]
+	}
+	if local >= end-v.offsets[source] {
+		return 0, false
+	}
+	return v.offsets[source] + local, true
+}
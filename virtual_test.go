@@ -0,0 +1,56 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestVirtualIndex(t *testing.T) {
+	v := NewVirtualIndex()
+	if _, _, ok := v.Gen(rand.New(rand.NewSource(1))); ok {
+		t.Errorf("Gen with no sources succeeded")
+	}
+
+	sources := [][]float64{{1, 2}, {3}, {1, 1, 2}}
+	var dist []float64
+	for i, weights := range sources {
+		s, err := v.Register(weights)
+		if err != nil {
+			t.Fatalf("Couldn't register source: %v", err)
+		}
+		if s != i {
+			t.Errorf("Source was numbered %v, wanted %v", s, i)
+		}
+		dist = append(dist, weights...)
+	}
+
+	checkDistribution(t, func(rng *rand.Rand) uint32 {
+		source, local, ok := v.Gen(rng)
+		if !ok {
+			t.Fatalf("Gen failed")
+		}
+		i, ok := v.Index(source, local)
+		if !ok {
+			t.Fatalf("Gen returned nonexistent item %v of source %v", local, source)
+		}
+		return i
+	}, dist, 1)
+
+	if s, l := v.Locate(3); s != 2 || l != 0 {
+		t.Errorf("Locate(3) was %v, %v, wanted 2, 0", s, l)
+	}
+	if _, ok := v.Index(1, 1); ok {
+		t.Errorf("Index of a nonexistent item succeeded")
+	}
+	if _, ok := v.Index(3, 0); ok {
+		t.Errorf("Index of a nonexistent source succeeded")
+	}
+
+	if _, err := v.Register([]float64{1, 0}); err == nil {
+		t.Errorf("Register with a zero weight did not fail")
+	}
+}
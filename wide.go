@@ -0,0 +1,140 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+)
+
+// Wide is an alias table with 63-bit thresholds, for distributions whose
+// smallest probabilities are too small for Alias.
+//
+// Alias quantizes each slot's threshold to 31 bits, so probabilities much
+// below 2^-31 are lost or badly rounded. Wide keeps the full 53-bit
+// precision of the float64 weights through to the thresholds, and compares
+// against them using all 63 bits of each draw. Its table takes twice the
+// memory of an Alias table, and Gen costs about the same.
+type Wide struct {
+	table []wpiece
+
+	profileLabel string // from Options; see profile.go
+
+	pmfOnce  sync.Once
+	pmfCache []float64
+}
+
+type wpiece struct {
+	prob  uint64 // [0,2^63)
+	alias uint32
+}
+
+// Create a new wide alias object. The probabilities are as for New.
+func NewWide(prob []float64) (*Wide, error) {
+	wd, _, err := buildWide(prob, vose)
+	return wd, err
+}
+
+// buildWide does the work of NewWide, pairing off the items with pair, and
+// returning the leftover mass as build does.
+func buildWide(prob []float64, pair pairFunc) (*Wide, float64, error) {
+	total, err := checkProb(prob, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	table := make([]wpiece, len(prob))
+	leftover := pair(prob, total, func(i uint32, p float64, alias uint32) {
+		// scaling by a power of two keeps every bit of p
+		q := uint64(1<<63 - 1)
+		if p < 1 {
+			q = uint64(p * (1 << 63))
+		}
+		table[i] = wpiece{q, alias}
+	})
+
+	return &Wide{table: table}, leftover, nil
+}
+
+// Len returns the number of items.
+func (wd *Wide) Len() int {
+	return len(wd.table)
+}
+
+// Generates a random number according to the distribution using the rng
+// passed. Like Alias.Gen, it takes exactly one Int63 from rng and never
+// retries.
+func (wd *Wide) Gen(rng *rand.Rand) uint32 {
+	return wd.genFrom(uint64(rng.Int63()))
+}
+
+// genFrom picks an index given a uniform random value in [0,2^63). As for
+// Alias, a threshold of zero keeps nothing.
+func (wd *Wide) genFrom(r uint64) uint32 {
+	w := r % uint64(len(wd.table))
+	if r > wd.table[w].prob || wd.table[w].prob == 0 {
+		return wd.table[w].alias
+	}
+	return uint32(w)
+}
+
+// Prob returns the probability that Gen returns i, as encoded in the table.
+// As with Alias.Prob, the probabilities are computed in O(n) on first use
+// and cached.
+func (wd *Wide) Prob(i uint32) float64 {
+	pmf := wd.pmf()
+	if int(i) >= len(pmf) {
+		return 0
+	}
+	return pmf[i]
+}
+
+// pmf returns the probability the table gives each index. It is computed on
+// first use and cached.
+func (wd *Wide) pmf() []float64 {
+	wd.pmfOnce.Do(func() {
+		withLabel(wd.profileLabel, "pmf", func(context.Context) {
+			wd.pmfCache = widePMF(wd.table)
+		})
+	})
+	return wd.pmfCache
+}
+
+// widePMF computes the probability table gives each index as tablePMF does,
+// but over 2^63 values.
+func widePMF(table []wpiece) []float64 {
+	const max = 1<<63 - 1
+	n := uint64(len(table))
+
+	counts := make([]uint64, n)
+	for w, piece := range table {
+		w := uint64(w)
+		total := (max-w)/n + 1
+		direct := uint64(0)
+		if piece.prob >= w && piece.prob > 0 {
+			direct = (piece.prob-w)/n + 1
+		}
+		counts[w] += direct
+		counts[piece.alias] += total - direct
+	}
+
+	pmf := make([]float64, n)
+	for i, c := range counts {
+		pmf[i] = float64(c) / (1 << 63)
+	}
+	return pmf
+}
+
+// fullSlots returns the number of slots that never return their alias.
+func (wd *Wide) fullSlots() int {
+	full := 0
+	for _, piece := range wd.table {
+		if piece.prob == 1<<63-1 {
+			full++
+		}
+	}
+	return full
+}
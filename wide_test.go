@@ -0,0 +1,62 @@
+// Copyright (c) 2012-2015, Jack Christopher Kastorff
+// All rights reserved.
+// BSD Licensed, see LICENSE for details.
+
+package alias
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestWide(t *testing.T) {
+	dist := []float64{10, 1, 2, 3, 20, 4}
+	wd, err := NewWide(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create Wide: %v", err)
+	}
+
+	checkDistribution(t, wd.Gen, dist, 1)
+
+	if _, err := NewWide(nil); err == nil {
+		t.Errorf("NewWide with no probabilities did not fail")
+	}
+}
+
+func TestWidePrecision(t *testing.T) {
+	// far below what a 31-bit threshold can represent
+	tiny := math.Ldexp(1, -45)
+	dist := []float64{1, tiny, 1}
+
+	wd, err := NewWide(dist)
+	if err != nil {
+		t.Fatalf("Couldn't create Wide: %v", err)
+	}
+
+	want := tiny / (2 + tiny)
+	if got := wd.Prob(1); math.Abs(got-want) > want*1e-6 {
+		t.Errorf("Prob(1) was %v, wanted %v", got, want)
+	}
+
+	total := float64(0)
+	for i := range dist {
+		total += wd.Prob(uint32(i))
+	}
+	if total != 1 {
+		t.Errorf("Probabilities sum to %v", total)
+	}
+
+	// the narrow table can't tell it apart from zero
+	a := mustNew(t, dist)
+	if p := a.Prob(1); math.Abs(p-want) < want*0.5 {
+		t.Errorf("Alias.Prob(1) was %v; test doesn't exercise precision", p)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		if wd.Gen(rng) > 2 {
+			t.Fatalf("Gen out of range")
+		}
+	}
+}